@@ -2,4 +2,4 @@
 
 const { runStopwatch } = require("../src/index");
 
-runStopwatch();
+runStopwatch(process.argv.slice(2));
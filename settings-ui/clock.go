@@ -0,0 +1,405 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+	"unicode"
+
+	"cli-timer-settings-ui/duration"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// clockTickMsg redraws the clock; it fires every tickRateMs like the
+// setInterval in runClock.
+type clockTickMsg time.Time
+
+// timerDoneMsg reports that the completion notification has been sent.
+type timerDoneMsg struct{}
+
+// clockModel is the Go counterpart of runClock in src/index.js. It reads the
+// same config the settings UI edits and draws with renderFrame.
+type clockModel struct {
+	mode        string
+	baseSeconds int
+	cfg         config
+	fonts       *fontLibrary
+	fontNames   []string
+	configPath  string
+	writePolicy configWritePolicy
+	admin       adminPolicy
+	now         func() time.Time
+	notify      func(cfg config, initialSeconds int)
+	// target, when set, is a wall-clock deadline from `timer until`. The
+	// remaining time is read from the wall clock on every tick, so it is
+	// right again straight after the machine wakes from sleep.
+	target time.Time
+	// title, when set, replaces "Timer" or "Stopwatch" in the header.
+	title string
+	// tags from --tag are written to the history with each session.
+	tags []string
+	// session feeds the history log; doneAt is when the timer reached zero.
+	session sessionTracker
+	doneAt  time.Time
+
+	anchor        time.Time
+	pausedElapsed time.Duration
+	paused        bool
+	done          bool
+	notified      bool
+	width         int
+	height        int
+}
+
+// wallNow is time.Now without its monotonic reading. Go measures durations
+// on the monotonic clock when it can, and on Linux that clock stops while
+// the machine is suspended, so a timer would fall behind after sleep. Wall
+// time matches the Date.now() the JS runtime counts with.
+func wallNow() time.Time {
+	return time.Now().Round(0)
+}
+
+func newClockModel(mode string, seconds int, payload statePayload) clockModel {
+	now := wallNow()
+	return clockModel{
+		mode:        mode,
+		baseSeconds: seconds,
+		cfg:         payload.Config,
+		fonts:       newFontLibrary(payload.FontDir),
+		fontNames:   payload.Fonts,
+		configPath:  payload.ConfigPath,
+		writePolicy: defaultConfigWritePolicy(),
+		now:         wallNow,
+		notify:      notifyTimerFinished,
+		anchor:      now,
+		session:     sessionTracker{started: now},
+	}
+}
+
+func (c clockModel) tickCmd() tea.Cmd {
+	return tea.Tick(time.Duration(sanitizeTickRate(c.cfg.TickRateMs))*time.Millisecond, func(t time.Time) tea.Msg {
+		return clockTickMsg(t)
+	})
+}
+
+func (c clockModel) Init() tea.Cmd {
+	return c.tickCmd()
+}
+
+func (c clockModel) elapsed() time.Duration {
+	if c.paused {
+		return c.pausedElapsed
+	}
+	return c.pausedElapsed + c.now().Sub(c.anchor)
+}
+
+func (c clockModel) displaySeconds() int {
+	if !c.target.IsZero() {
+		return duration.Spec{Target: c.target}.Seconds(c.now())
+	}
+	elapsed := int(c.elapsed() / time.Second)
+	if c.mode == "timer" {
+		if remaining := c.baseSeconds - elapsed; remaining > 0 {
+			return remaining
+		}
+		return 0
+	}
+	return elapsed
+}
+
+// refreshDone stops a finished timer and returns the notification command
+// the first time it finishes.
+func (c *clockModel) refreshDone() tea.Cmd {
+	if c.mode != "timer" || c.done || c.displaySeconds() > 0 {
+		return nil
+	}
+	c.pausedElapsed = c.elapsed()
+	c.done = true
+	c.paused = true
+	c.doneAt = c.now()
+	if c.notified {
+		return nil
+	}
+	c.notified = true
+	cfg, seconds, notify := c.cfg, c.baseSeconds, c.notify
+	return func() tea.Msg {
+		notify(cfg, seconds)
+		return timerDoneMsg{}
+	}
+}
+
+func (c *clockModel) togglePause() {
+	if c.paused {
+		c.paused = false
+		c.anchor = c.now()
+		c.session.resume(c.anchor)
+		return
+	}
+	c.pausedElapsed = c.elapsed()
+	c.paused = true
+	c.session.pause(c.now())
+}
+
+func (c *clockModel) restart() {
+	c.session.resume(c.now())
+	c.paused = false
+	c.done = false
+	c.doneAt = time.Time{}
+	c.notified = false
+	c.pausedElapsed = 0
+	c.anchor = c.now()
+}
+
+// randomStyleFonts mirrors getRandomStyleFonts in src/index.js.
+func randomStyleFonts(cfg config, fonts []string) []string {
+	if cfg.RandomFromFavorites {
+		var favorites []string
+		for _, font := range fonts {
+			if containsString(cfg.FavoriteFonts, font) {
+				favorites = append(favorites, font)
+			}
+		}
+		if len(favorites) > 0 {
+			return favorites
+		}
+	}
+	return fonts
+}
+
+var styleRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func pickRandomFont(fonts []string, current string) string {
+	var candidates []string
+	for _, font := range fonts {
+		if font != current {
+			candidates = append(candidates, font)
+		}
+	}
+	if len(candidates) == 0 {
+		return current
+	}
+	return candidates[styleRand.Intn(len(candidates))]
+}
+
+// cycleStyle switches to a random font and remembers it in the config file,
+// like the style key in the JS runtime. A locked font is left alone.
+func (c *clockModel) cycleStyle() {
+	if _, locked := c.admin.locked["font"]; locked {
+		return
+	}
+	next := pickRandomFont(randomStyleFonts(c.cfg, c.fontNames), c.cfg.Font)
+	if next == "" || next == c.cfg.Font {
+		return
+	}
+	c.cfg.Font = next
+	if c.configPath == "" {
+		return
+	}
+	if saved, err := readConfigFile(c.configPath); err == nil {
+		saved.Font = next
+		_ = c.writePolicy.writeConfigFile(c.configPath, saved)
+	}
+}
+
+// clockKeyToken turns a key press into a keybindings token, as
+// keyTokenFromInput does in src/index.js.
+func clockKeyToken(msg tea.KeyMsg) string {
+	if msg.Type == tea.KeySpace {
+		return "space"
+	}
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return ""
+	}
+	if msg.Runes[0] == ' ' {
+		return "space"
+	}
+	return string(unicode.ToLower(msg.Runes[0]))
+}
+
+func (c clockModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.width, c.height = msg.Width, msg.Height
+		return c, nil
+	case clockTickMsg:
+		return c, tea.Batch(c.refreshDone(), c.tickCmd())
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return c, tea.Quit
+		}
+		kb := c.cfg.Keybindings
+		switch token := clockKeyToken(msg); {
+		case token == "":
+			return c, nil
+		case token == kb.PauseKey || token == kb.PauseAltKey:
+			// A deadline keeps running whatever the timer shows.
+			if !c.done && c.target.IsZero() {
+				c.togglePause()
+			}
+		case token == kb.RestartKey:
+			if c.target.IsZero() {
+				c.restart()
+			}
+		case token == kb.StyleKey:
+			c.cycleStyle()
+		case token == kb.ExitKey || token == kb.ExitAltKey:
+			return c, tea.Quit
+		}
+		return c, c.refreshDone()
+	}
+	return c, nil
+}
+
+func (c clockModel) state() frameState {
+	switch {
+	case c.done:
+		return frameDone
+	case c.paused:
+		return framePaused
+	default:
+		return frameRunning
+	}
+}
+
+func (c clockModel) View() string {
+	spec := frameSpec{
+		mode:    c.mode,
+		seconds: c.displaySeconds(),
+		state:   c.state(),
+		cfg:     c.cfg,
+		width:   c.width,
+		height:  c.height,
+		title:   c.title,
+	}
+	if !c.target.IsZero() {
+		spec.subtitle = targetSubtitle(c.target)
+	}
+	return renderFrame(c.fonts, spec)
+}
+
+// runNonInteractiveTimer prints the remaining time once per second when
+// there is no terminal to draw on, like runNonInteractiveTimer in the JS.
+func runNonInteractiveTimer(deadline time.Time, initialSeconds int, cfg config) {
+	last := -1
+	for {
+		remaining := duration.Spec{Target: deadline}.Seconds(wallNow())
+		if remaining != last {
+			last = remaining
+			fmt.Println(formatClock(remaining, cfg.DayFormat))
+		}
+		if remaining == 0 {
+			notifyTimerFinished(cfg, initialSeconds)
+			return
+		}
+		time.Sleep(time.Duration(sanitizeTickRate(cfg.TickRateMs)) * time.Millisecond)
+	}
+}
+
+func printDurationError(err error) {
+	fmt.Fprintf(os.Stderr, "%v\n", err)
+	var parseErr *duration.ParseError
+	if errors.As(err, &parseErr) && parseErr.Pointer() != "" {
+		fmt.Fprintf(os.Stderr, "\n%s\n", parseErr.Pointer())
+	}
+	fmt.Fprintf(os.Stderr, "\n%s\n", commandUsage)
+}
+
+const commandUsage = `Usage:
+  cli-timer-settings-ui [--config-path FILE] [--font-dir DIR] timer <duration> [--tz ZONE] [--label TEXT] [--tag TAG]...   (5 min 2 sec, 1h30m, 5:00, PT25M, until 17:30, at 9am tomorrow)
+  cli-timer-settings-ui [--config-path FILE] [--font-dir DIR] stopwatch [--export FILE] [--format csv|json] [--label TEXT] [--tag TAG]...
+  cli-timer-settings-ui [--config-path FILE] [--font-dir DIR] preset [NAME] [--label TEXT] [--tag TAG]...   (no name lists the presets)
+  cli-timer-settings-ui [--config-path FILE] [--font-dir DIR] pomodoro [--tag TAG]...
+  cli-timer-settings-ui [--config-path FILE] [--font-dir DIR] plan [NAME | FILE] [--tag TAG]...   (no argument lists the plan directory)
+  cli-timer-settings-ui [--config-path FILE] [--font-dir DIR] dashboard [--add "NAME DURATION"]... [--tag TAG]...
+  cli-timer-settings-ui [--config-path FILE] report [--from DATE] [--to DATE] [--tag TAG]... [--format table|csv|json] [--output FILE]`
+
+// runTimerCommand runs `timer <duration>` from the same config the settings
+// UI edits, with the admin policy applied. Targets such as `until 17:30` are
+// read in the --tz zone, falling back to the configured default zone.
+func runTimerCommand(args []string, configPath string, fontDir string, policy configWritePolicy, admin adminPolicy) int {
+	session, args, err := extractSessionTags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	zones, args, err := extractFlag(args, "tz")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	payload, err := loadStandalonePayload(configPath, fontDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	payload.Config = admin.apply(payload.Config)
+
+	zone := payload.Config.TimeZone
+	if len(zones) > 0 {
+		zone = lastValue(zones)
+	}
+	loc, err := loadTimeZone(zone)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	now := wallNow()
+	spec, err := duration.Parse(args, now.In(loc))
+	if err != nil {
+		printDurationError(err)
+		return 1
+	}
+	return runCountdown(payload, spec, now, session, policy, admin)
+}
+
+// runCountdown runs a parsed timer from now: full screen on a terminal,
+// otherwise as plain lines. A label names the timer in the header and the
+// notification; label and tags are logged to the history.
+func runCountdown(payload statePayload, spec duration.Spec, now time.Time, session sessionTags, policy configWritePolicy, admin adminPolicy) int {
+	seconds := spec.Seconds(now)
+	deadline := now.Add(spec.Duration)
+	if spec.IsTarget() {
+		deadline = spec.Target
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		runNonInteractiveTimer(deadline, seconds, payload.Config)
+		end := time.Now().Truncate(time.Second)
+		recordSessions(payload, historyEntry{
+			Mode:           "timer",
+			Label:          session.label,
+			Tags:           session.tags,
+			Start:          now.Truncate(time.Second),
+			End:            end,
+			PlannedSeconds: seconds,
+			ActualSeconds:  int(end.Sub(now.Truncate(time.Second)) / time.Second),
+			Completed:      true,
+		})
+		return 0
+	}
+
+	clock := newClockModel("timer", seconds, payload)
+	clock.target = spec.Target
+	clock.writePolicy = policy
+	clock.admin = admin
+	clock.title = session.label
+	clock.tags = session.tags
+	if session.label != "" {
+		clock.notify = func(cfg config, initialSeconds int) {
+			notifyFinished(fmt.Sprintf("%s finished (%s)", session.label, formatHms(initialSeconds)), cfg)
+		}
+	}
+	final, err := tea.NewProgram(clock, tea.WithAltScreen()).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Timer failed: %v\n", err)
+		return 1
+	}
+	if final, ok := final.(clockModel); ok {
+		recordSessions(payload, final.historyEntry(session.label))
+	}
+	return 0
+}
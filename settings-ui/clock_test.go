@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testClock(seconds int) (clockModel, *fakeClock) {
+	c := newClockModel("timer", seconds, testPayload())
+	fake := newFakeClock(&c)
+	c.notify = func(config, int) {}
+	return c, fake
+}
+
+func TestClockCountsOnWallTime(t *testing.T) {
+	c := newClockModel("timer", 60, testPayload())
+	// A monotonic reading shows up as "m=+1.23" and would make elapsed
+	// ignore time spent suspended.
+	for _, tm := range []time.Time{c.anchor, c.session.started, c.now()} {
+		if strings.Contains(tm.String(), "m=") {
+			t.Fatalf("expected wall-clock times, got %s", tm)
+		}
+	}
+}
+
+func TestClockCountsDownAndPauses(t *testing.T) {
+	c, fake := testClock(10)
+	fake.t = fake.t.Add(3 * time.Second)
+	if got := c.displaySeconds(); got != 7 {
+		t.Fatalf("expected 7 seconds left, got %d", got)
+	}
+
+	c = press(c, runeKey(" "))
+	fake.t = fake.t.Add(5 * time.Second)
+	if got := c.displaySeconds(); got != 7 || c.state() != framePaused {
+		t.Fatalf("expected paused at 7 seconds, got %d (%v)", got, c.state())
+	}
+
+	c = press(c, runeKey("P"))
+	fake.t = fake.t.Add(time.Second)
+	if got := c.displaySeconds(); got != 6 {
+		t.Fatalf("expected resume to continue from 7, got %d", got)
+	}
+
+	c = press(c, runeKey("r"))
+	if got := c.displaySeconds(); got != 10 {
+		t.Fatalf("expected restart to reset to 10, got %d", got)
+	}
+}
+
+func TestClockNotifiesOnceWhenDone(t *testing.T) {
+	c, fake := testClock(2)
+	calls := 0
+	c.notify = func(cfg config, seconds int) {
+		calls++
+		if seconds != 2 {
+			t.Fatalf("expected initial seconds 2, got %d", seconds)
+		}
+	}
+	fake.t = fake.t.Add(3 * time.Second)
+
+	updated, cmd := c.Update(clockTickMsg(fake.t))
+	c = updated.(clockModel)
+	if !c.done || c.state() != frameDone {
+		t.Fatalf("expected timer to be done")
+	}
+	for _, inner := range cmd().(tea.BatchMsg) {
+		if inner != nil {
+			if _, ok := inner().(timerDoneMsg); ok {
+				break
+			}
+		}
+	}
+	updated, cmd = c.Update(clockTickMsg(fake.t))
+	for _, inner := range cmd().(tea.BatchMsg) {
+		if inner != nil {
+			if _, ok := inner().(timerDoneMsg); ok {
+				t.Fatalf("expected no second notification")
+			}
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one notification, got %d", calls)
+	}
+
+	c = press(updated.(clockModel), runeKey(" "))
+	if !c.done {
+		t.Fatalf("expected pause to do nothing once done")
+	}
+}
+
+func TestClockUsesCustomKeybindings(t *testing.T) {
+	c, _ := testClock(60)
+	c.cfg.Keybindings.ExitKey = "x"
+	if _, cmd := c.Update(runeKey("x")); cmd == nil {
+		t.Fatalf("expected custom exit key to quit")
+	}
+	if _, cmd := c.Update(runeKey("q")); cmd != nil {
+		t.Fatalf("expected the default exit key to be unbound")
+	}
+}
+
+func TestClockStylePersistsFontUnlessLocked(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	c, _ := testClock(60)
+	c.configPath = path
+	c.writePolicy = configWritePolicy{dir: dir}
+
+	c = press(c, runeKey("f"))
+	if c.cfg.Font != "Big" {
+		t.Fatalf("expected style key to switch to Big, got %s", c.cfg.Font)
+	}
+	saved, err := readConfigFile(path)
+	if err != nil || saved.Font != "Big" {
+		t.Fatalf("expected font saved to config, got %q (%v)", saved.Font, err)
+	}
+
+	os.Remove(path)
+	c.admin = adminPolicy{locked: map[string]interface{}{"font": "Big"}}
+	c = press(c, runeKey("f"))
+	if c.cfg.Font != "Big" {
+		t.Fatalf("expected locked font to stay, got %s", c.cfg.Font)
+	}
+}
+
+func TestRandomStyleFontsPrefersFavorites(t *testing.T) {
+	cfg := config{RandomFromFavorites: true, FavoriteFonts: []string{"Big", "Missing"}}
+	got := randomStyleFonts(cfg, []string{"Standard", "Big", "Slant"})
+	if strings.Join(got, ",") != "Big" {
+		t.Fatalf("expected favorites only, got %v", got)
+	}
+	cfg.FavoriteFonts = []string{"Missing"}
+	if got := randomStyleFonts(cfg, []string{"Standard", "Big"}); len(got) != 2 {
+		t.Fatalf("expected fallback to all fonts, got %v", got)
+	}
+}
+
+func TestClockViewFollowsWindowSize(t *testing.T) {
+	c, _ := testClock(65)
+	updated, _ := c.Update(tea.WindowSizeMsg{Width: 40, Height: 10})
+	view := updated.(clockModel).View()
+	lines := strings.Split(view, "\n")
+	if len(lines) > 10 {
+		t.Fatalf("expected at most 10 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if len([]rune(line)) > 40 {
+			t.Fatalf("expected lines clipped to 40 columns, got %q", line)
+		}
+	}
+	if !strings.Contains(view, "Timer | Font: Standard") {
+		t.Fatalf("expected header in view:\n%s", view)
+	}
+}
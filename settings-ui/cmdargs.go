@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// extractFlag pulls every `--name value` or `--name=value` out of args, so
+// subcommand flags can sit anywhere among positional words such as
+// `timer until 17:30 --tz Europe/Berlin`. The flag package stops at the
+// first positional argument, which is why it is not used here.
+func extractFlag(args []string, name string) (values []string, rest []string, err error) {
+	long, short := "--"+name, "-"+name
+	for idx := 0; idx < len(args); idx++ {
+		arg := args[idx]
+		switch {
+		case arg == long || arg == short:
+			if idx+1 >= len(args) {
+				return nil, nil, fmt.Errorf("%s needs a value", long)
+			}
+			values = append(values, args[idx+1])
+			idx++
+		case strings.HasPrefix(arg, long+"="):
+			values = append(values, strings.TrimPrefix(arg, long+"="))
+		case strings.HasPrefix(arg, short+"="):
+			values = append(values, strings.TrimPrefix(arg, short+"="))
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return values, rest, nil
+}
+
+// lastValue is the value of a flag that may only be given once; a repeat
+// wins, as with the flag package.
+func lastValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+const (
+	maxSessionLabelLen = 60
+	maxSessionTags     = 10
+	maxSessionTagLen   = 32
+)
+
+// sessionTags is the --label and --tag of a run, written to the history
+// and summed up by `report`.
+type sessionTags struct {
+	label string
+	tags  []string
+}
+
+// normalizeTags splits comma-separated values, so `--tag work,client`
+// equals `--tag work --tag client`, and drops repeats ignoring case.
+func normalizeTags(values []string) ([]string, error) {
+	var tags []string
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || containsFold(tags, tag) {
+				continue
+			}
+			if len([]rune(tag)) > maxSessionTagLen {
+				return nil, fmt.Errorf("tag %q is longer than %d characters", tag, maxSessionTagLen)
+			}
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxSessionTags {
+		return nil, fmt.Errorf("at most %d tags can be given", maxSessionTags)
+	}
+	return tags, nil
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractSessionTags pulls --label and every --tag out of args.
+func extractSessionTags(args []string) (sessionTags, []string, error) {
+	labels, rest, err := extractFlag(args, "label")
+	if err != nil {
+		return sessionTags{}, nil, err
+	}
+	tagValues, rest, err := extractFlag(rest, "tag")
+	if err != nil {
+		return sessionTags{}, nil, err
+	}
+	label := strings.Join(strings.Fields(lastValue(labels)), " ")
+	if len([]rune(label)) > maxSessionLabelLen {
+		return sessionTags{}, nil, fmt.Errorf("--label is longer than %d characters", maxSessionLabelLen)
+	}
+	tags, err := normalizeTags(tagValues)
+	if err != nil {
+		return sessionTags{}, nil, err
+	}
+	return sessionTags{label: label, tags: tags}, rest, nil
+}
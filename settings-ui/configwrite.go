@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const configDirMode = 0700
+
+// configWritePolicy decides where save may write. The config path arrives in
+// the state payload, which anything able to write the state file controls, so
+// the trusted directory comes from the binary's own flags and environment.
+type configWritePolicy struct {
+	// dir is the only directory the config file may live in. Files must sit
+	// directly in it: a subdirectory could be a symlink to anywhere.
+	dir string
+	// allowSymlinks lets an existing symlinked config file be written
+	// through, for dotfile managers that link it elsewhere.
+	allowSymlinks bool
+}
+
+// defaultConfigWritePolicy trusts the config dir the timer CLI itself would
+// use.
+func defaultConfigWritePolicy() configWritePolicy {
+	path, err := defaultConfigPath()
+	if err != nil {
+		return configWritePolicy{}
+	}
+	return configWritePolicy{dir: filepath.Dir(path)}
+}
+
+func checkParentDir(dir string) error {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("config directory %s is not a directory", dir)
+	}
+	// Permission bits carry no meaning on Windows.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0002 != 0 {
+		return fmt.Errorf("refusing to write config: directory %s is world-writable", dir)
+	}
+	return nil
+}
+
+// validateConfigPath returns the file save should write: path itself, or the
+// target of a symlinked config file when the policy allows it.
+func (p configWritePolicy) validateConfigPath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("config path is missing")
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("config path %q is not absolute", path)
+	}
+	if p.dir == "" {
+		return "", fmt.Errorf("config directory could not be determined")
+	}
+	path = filepath.Clean(path)
+	dir := filepath.Clean(p.dir)
+	if filepath.Dir(path) != dir {
+		return "", fmt.Errorf("config path %s is outside the config directory %s", path, dir)
+	}
+
+	target := path
+	info, err := os.Lstat(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return "", err
+	case info.Mode()&os.ModeSymlink != 0:
+		if !p.allowSymlinks {
+			return "", fmt.Errorf("config path %s is a symlink (pass --allow-symlinks to write through it)", path)
+		}
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return "", fmt.Errorf("config path %s: %w", path, err)
+		}
+		resolvedInfo, err := os.Stat(resolved)
+		if err != nil {
+			return "", err
+		}
+		if !resolvedInfo.Mode().IsRegular() {
+			return "", fmt.Errorf("config path %s does not point to a regular file", path)
+		}
+		target = resolved
+	case !info.Mode().IsRegular():
+		return "", fmt.Errorf("config path %s is not a regular file", path)
+	}
+
+	if err := checkParentDir(filepath.Dir(target)); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// writeConfigFile validates path against the policy and replaces the file
+// atomically with owner-only permissions.
+func (p configWritePolicy) writeConfigFile(path string, cfg config) error {
+	return p.writeJSONFile(path, cfg)
+}
+
+// writeJSONFile is writeConfigFile for the other files kept in the config
+// dir, such as the UI state.
+func (p configWritePolicy) writeJSONFile(path string, value interface{}) error {
+	target, err := p.validateConfigPath(path)
+	if err != nil {
+		return err
+	}
+	text, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	text = append(text, '\n')
+
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, configDirMode); err != nil {
+		return err
+	}
+
+	// CreateTemp opens the file as 0600, and renaming it over a symlink
+	// replaces the link instead of following it.
+	tmp, err := os.CreateTemp(dir, ".config-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(text); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// checkStateFile refuses a state file that someone else could have swapped in
+// or edited: a symlink, or a file other users may write.
+func checkStateFile(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("state file %s is a symlink", path)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("state file %s is not a regular file", path)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0002 != 0 {
+		return fmt.Errorf("state file %s is world-writable", path)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWriteConfigFileCreatesOwnerOnlyFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cli-timer")
+	path := filepath.Join(dir, "config.json")
+	policy := configWritePolicy{dir: dir}
+
+	if err := policy.writeConfigFile(path, defaultConfig()); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil || cfg.Font != defaultFont {
+		t.Fatalf("expected config to round-trip, got %+v err=%v", cfg, err)
+	}
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestWriteConfigFileTightensExistingFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (configWritePolicy{dir: dir}).writeConfigFile(path, defaultConfig()); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func expectConfigPathError(t *testing.T, policy configWritePolicy, path string, want string) {
+	t.Helper()
+	err := policy.writeConfigFile(path, defaultConfig())
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected error containing %q, got %v", want, err)
+	}
+}
+
+func TestWriteConfigFileRejectsRelativePath(t *testing.T) {
+	expectConfigPathError(t, configWritePolicy{dir: t.TempDir()}, "config.json", "not absolute")
+}
+
+func TestWriteConfigFileRejectsPathOutsideConfigDir(t *testing.T) {
+	root := t.TempDir()
+	policy := configWritePolicy{dir: filepath.Join(root, "cli-timer")}
+	expectConfigPathError(t, policy, filepath.Join(root, "elsewhere.json"), "outside the config directory")
+	expectConfigPathError(t, policy, filepath.Join(root, "cli-timer", "..", "escape.json"), "outside the config directory")
+	expectConfigPathError(t, policy, filepath.Join(root, "cli-timer-other", "config.json"), "outside the config directory")
+}
+
+func TestWriteConfigFileRejectsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.Mkdir(path, 0700); err != nil {
+		t.Fatal(err)
+	}
+	expectConfigPathError(t, configWritePolicy{dir: dir}, path, "not a regular file")
+}
+
+func TestWriteConfigFileSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(t.TempDir(), "dotfiles-config.json")
+	if err := os.WriteFile(target, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := os.Symlink(target, path); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	expectConfigPathError(t, configWritePolicy{dir: dir}, path, "is a symlink")
+
+	if err := (configWritePolicy{dir: dir, allowSymlinks: true}).writeConfigFile(path, defaultConfig()); err != nil {
+		t.Fatalf("expected write through allowed symlink, got %v", err)
+	}
+	if info, err := os.Lstat(path); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected the symlink to be kept")
+	}
+	if cfg, err := readConfigFile(target); err != nil || cfg.Font != defaultFont {
+		t.Fatalf("expected the link target to be written, got %+v err=%v", cfg, err)
+	}
+}
+
+func TestWriteConfigFileRejectsSymlinkedParentDir(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dir, "x")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	path := filepath.Join(dir, "x", "config.json")
+	expectConfigPathError(t, configWritePolicy{dir: dir, allowSymlinks: true}, path, "outside the config directory")
+	if _, err := os.Stat(filepath.Join(outside, "config.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing written through the symlinked directory, got %v", err)
+	}
+}
+
+func TestWriteConfigFileRejectsWorldWritableParent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on Windows")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	expectConfigPathError(t, configWritePolicy{dir: dir}, filepath.Join(dir, "config.json"), "world-writable")
+}
+
+func TestCheckStateFileRejectsUntrustedFiles(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "state.json")
+	if err := os.WriteFile(good, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkStateFile(good); err != nil {
+		t.Fatalf("expected private state file to pass, got %v", err)
+	}
+
+	link := filepath.Join(dir, "link.json")
+	if err := os.Symlink(good, link); err == nil {
+		if err := checkStateFile(link); err == nil || !strings.Contains(err.Error(), "symlink") {
+			t.Fatalf("expected symlinked state file to be rejected, got %v", err)
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if err := os.Chmod(good, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkStateFile(good); err == nil || !strings.Contains(err.Error(), "world-writable") {
+		t.Fatalf("expected world-writable state file to be rejected, got %v", err)
+	}
+}
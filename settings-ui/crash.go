@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// crashReport records a panic caught in Update or View. It is shared by
+// pointer because View has a value receiver and cannot hand state back.
+type crashReport struct {
+	err   error
+	stack []byte
+}
+
+func (c *crashReport) record(r interface{}) {
+	if c.err != nil {
+		return
+	}
+	c.err = fmt.Errorf("%v", r)
+	c.stack = debug.Stack()
+}
+
+func (c *crashReport) crashed() bool {
+	return c != nil && c.err != nil
+}
+
+// Update wraps update with draft autosave and panic recovery. A panic leaves
+// the model as it was before the message, which is what the draft holds, and
+// quits through Bubble Tea so the terminal is restored.
+func (m model) Update(msg tea.Msg) (next tea.Model, cmd tea.Cmd) {
+	if m.crash.crashed() {
+		m.quitting = true
+		return m, tea.Quit
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			m.crash.record(r)
+			m.quitting = true
+			next, cmd = m, tea.Quit
+		}
+	}()
+
+	before := m.payload.Config
+	updated, cmd := m.update(msg)
+	after := updated.(model)
+	if !after.quitting && !reflect.DeepEqual(before, after.payload.Config) {
+		after.writeDraft()
+	}
+	return after, cmd
+}
+
+// View wraps view with panic recovery. The next message quits; see Update.
+func (m model) View() (out string) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.crash.record(r)
+			out = m.crashView()
+		}
+	}()
+	if m.crash.crashed() {
+		return m.crashView()
+	}
+	return m.view()
+}
+
+func (m model) crashView() string {
+	if m.quitting {
+		return fmt.Sprintf("Settings UI crashed: %v\n", m.crash.err)
+	}
+	return fmt.Sprintf("The settings UI hit an internal error: %v\n\nPress any key to exit. Unsaved changes are kept in a draft.\n", m.crash.err)
+}
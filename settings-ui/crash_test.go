@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestViewPanicIsRecoveredAndQuits(t *testing.T) {
+	m := newModel(testPayload())
+	// An out-of-range saved rate with no valid input divides by zero in the
+	// live sample.
+	m.payload.Config.TickRateMs = 0
+	m.tickInput.SetValue("")
+	m.screen = screenTickRateEditor
+
+	if view := m.View(); !strings.Contains(view, "internal error") {
+		t.Fatalf("expected crash notice, got:\n%s", view)
+	}
+	if !m.crash.crashed() {
+		t.Fatalf("expected the panic to be recorded")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	next := updated.(model)
+	if !next.quitting || cmd == nil {
+		t.Fatalf("expected the next key to quit")
+	}
+	if !strings.Contains(next.View(), "crashed") {
+		t.Fatalf("expected final crash view, got %q", next.View())
+	}
+}
+
+func TestProtocolReportsCrashAsError(t *testing.T) {
+	m := newModel(testPayload())
+	m.crash.record("boom")
+	result := protocolResultFor(protocolVersion, m.payload.Config, m, nil)
+	if result.Status != protocolStatusError || !strings.Contains(result.Error, "boom") {
+		t.Fatalf("expected crash to be reported as error, got %+v", result)
+	}
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"cli-timer-settings-ui/duration"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+const (
+	maxDashboardRows  = 20
+	dashboardBarWidth = 20
+)
+
+var (
+	dashboardFocusStyle = lipgloss.NewStyle().Bold(true).Reverse(true)
+	dashboardDoneStyle  = lipgloss.NewStyle().Bold(true)
+)
+
+// dashboardRow is one named timer or stopwatch on the dashboard. Each row
+// has its own clock, so pause, restart and completion work exactly as in
+// `timer` and `stopwatch`.
+type dashboardRow struct {
+	name  string
+	clock clockModel
+	// total is the full length of a timer, used for the progress bar; for
+	// a target it is the time from adding the row to the deadline.
+	total int
+}
+
+func (r dashboardRow) kind() string {
+	switch {
+	case r.clock.mode == "stopwatch":
+		return "Stopwatch"
+	case !r.clock.target.IsZero():
+		return "Until " + r.clock.target.Format("15:04")
+	default:
+		return "Timer"
+	}
+}
+
+func (r dashboardRow) stateName() string {
+	return frameStateNames[r.clock.state()]
+}
+
+// progressBar shows how much of a timer has run; stopwatches have none.
+func (r dashboardRow) progressBar() string {
+	if r.clock.mode == "stopwatch" || r.total <= 0 {
+		return ""
+	}
+	done := r.total - r.clock.displaySeconds()
+	if done < 0 {
+		done = 0
+	}
+	filled := done * dashboardBarWidth / r.total
+	if filled > dashboardBarWidth {
+		filled = dashboardBarWidth
+	}
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("=", filled), strings.Repeat("-", dashboardBarWidth-filled), done*100/r.total)
+}
+
+// dashboardModel runs several timers and stopwatches side by side, one row
+// each. The focused row takes the pause, restart and delete keys from the
+// configured keybindings.
+type dashboardModel struct {
+	payload statePayload
+	rows    []dashboardRow
+	focus   int
+	// adding is set while the new-timer input is open.
+	adding bool
+	input  textinput.Model
+	err    error
+	// tags from --tag are logged with every row's session; removed holds
+	// the sessions of deleted rows until exit.
+	tags    []string
+	removed []historyEntry
+	now     func() time.Time
+	width   int
+	height  int
+}
+
+func newDashboardModel(payload statePayload) dashboardModel {
+	input := textinput.New()
+	input.Prompt = "New timer: "
+	input.Placeholder = "tea 4 min, standup until 10:00, build (stopwatch)"
+	input.CharLimit = 80
+	return dashboardModel{payload: payload, input: input, now: wallNow}
+}
+
+// parseDashboardTimer splits "laundry 45 min" into a name and a duration:
+// the longest run of trailing words that parses as a duration or target.
+// Text without one is a preset name or, failing that, a stopwatch.
+func parseDashboardTimer(text string, presets []timerPreset, now time.Time) (name string, spec duration.Spec, stopwatch bool, err error) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return "", duration.Spec{}, false, errors.New("type a name and a duration, e.g. tea 4 min")
+	}
+	for idx := range words {
+		if spec, err := duration.Parse(words[idx:], now); err == nil {
+			return strings.Join(words[:idx], " "), spec, false, nil
+		}
+	}
+	name = strings.Join(words, " ")
+	if p, ok := findTimerPreset(presets, name); ok {
+		spec, err := parsePresetDuration(p.Duration)
+		if err != nil {
+			return "", duration.Spec{}, false, fmt.Errorf("preset %q: %w", p.Name, err)
+		}
+		return p.Name, spec, false, nil
+	}
+	return name, duration.Spec{}, true, nil
+}
+
+// addTimer adds a row from text as typed in the view or given to --add.
+func (m *dashboardModel) addTimer(text string) error {
+	if len(m.rows) >= maxDashboardRows {
+		return fmt.Errorf("the dashboard holds at most %d timers", maxDashboardRows)
+	}
+	now := m.now()
+	loc, err := loadTimeZone(m.payload.Config.TimeZone)
+	if err != nil {
+		loc = time.Local
+	}
+	name, spec, stopwatch, err := parseDashboardTimer(text, m.payload.Config.Presets, now.In(loc))
+	if err != nil {
+		return err
+	}
+	if len([]rune(name)) > maxSessionLabelLen {
+		return fmt.Errorf("names are at most %d characters", maxSessionLabelLen)
+	}
+	if name == "" {
+		name = fmt.Sprintf("Timer %d", len(m.rows)+1)
+	}
+
+	row := dashboardRow{name: name}
+	if stopwatch {
+		row.clock = newClockModel("stopwatch", 0, m.payload)
+	} else {
+		row.total = spec.Seconds(now)
+		if row.total <= 0 {
+			return fmt.Errorf("%s is already over", strings.TrimSpace(strings.TrimPrefix(text, name)))
+		}
+		row.clock = newClockModel("timer", row.total, m.payload)
+		row.clock.target = spec.Target
+	}
+	row.clock.now = m.now
+	row.clock.anchor = now
+	row.clock.session = sessionTracker{started: now}
+	row.clock.title = name
+	row.clock.tags = m.tags
+	row.clock.notify = func(cfg config, initialSeconds int) {
+		notifyFinished(fmt.Sprintf("%s finished (%s)", name, formatHms(initialSeconds)), cfg)
+	}
+	m.rows = append(m.rows, row)
+	m.focus = len(m.rows) - 1
+	return nil
+}
+
+func (m *dashboardModel) deleteFocused() {
+	if len(m.rows) == 0 {
+		return
+	}
+	row := m.rows[m.focus]
+	m.removed = append(m.removed, row.clock.historyEntry(row.name))
+	m.rows = append(m.rows[:m.focus:m.focus], m.rows[m.focus+1:]...)
+	if m.focus >= len(m.rows) && m.focus > 0 {
+		m.focus--
+	}
+}
+
+func (m *dashboardModel) moveFocus(delta int) {
+	if len(m.rows) == 0 {
+		return
+	}
+	m.focus = ((m.focus+delta)%len(m.rows) + len(m.rows)) % len(m.rows)
+}
+
+// sessions is every row's history entry, deleted rows included.
+func (m dashboardModel) sessions() []historyEntry {
+	entries := append([]historyEntry{}, m.removed...)
+	for _, row := range m.rows {
+		entries = append(entries, row.clock.historyEntry(row.name))
+	}
+	return entries
+}
+
+func (m dashboardModel) tickCmd() tea.Cmd {
+	return tea.Tick(time.Duration(sanitizeTickRate(m.payload.Config.TickRateMs))*time.Millisecond, func(t time.Time) tea.Msg {
+		return clockTickMsg(t)
+	})
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return m.tickCmd()
+}
+
+// refreshRows stops finished timers and collects their notifications.
+func (m *dashboardModel) refreshRows() tea.Cmd {
+	var cmds []tea.Cmd
+	for idx := range m.rows {
+		cmds = append(cmds, m.rows[idx].clock.refreshDone())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case clockTickMsg:
+		return m, tea.Batch(m.refreshRows(), m.tickCmd())
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.adding {
+			cmd := m.updateInput(msg)
+			return m, cmd
+		}
+		m.err = nil
+		if m.quitting(msg) {
+			return m, tea.Quit
+		}
+		m.updateKeys(msg)
+		if m.adding {
+			return m, textinput.Blink
+		}
+		return m, m.refreshRows()
+	}
+	return m, nil
+}
+
+func (m dashboardModel) quitting(msg tea.KeyMsg) bool {
+	kb := m.payload.Config.Keybindings
+	token := clockKeyToken(msg)
+	return token != "" && (token == kb.ExitKey || token == kb.ExitAltKey)
+}
+
+func (m *dashboardModel) updateInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.adding = false
+		m.err = nil
+		m.input.Blur()
+		return nil
+	case tea.KeyEnter:
+		if err := m.addTimer(m.input.Value()); err != nil {
+			m.err = err
+			return nil
+		}
+		m.adding = false
+		m.err = nil
+		m.input.Blur()
+		return nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd
+}
+
+// updateKeys handles a key outside the input. The configured keybindings
+// come first; the fixed navigation and add keys only apply when they are
+// not bound to anything else.
+func (m *dashboardModel) updateKeys(msg tea.KeyMsg) {
+	kb := m.payload.Config.Keybindings
+	switch msg.Type {
+	case tea.KeyUp:
+		m.moveFocus(-1)
+		return
+	case tea.KeyDown:
+		m.moveFocus(1)
+		return
+	}
+	token := clockKeyToken(msg)
+	if token == "" {
+		return
+	}
+	var row *clockModel
+	if len(m.rows) > 0 {
+		row = &m.rows[m.focus].clock
+	}
+	switch token {
+	case kb.PauseKey, kb.PauseAltKey:
+		// A deadline keeps running whatever the row shows.
+		if row != nil && !row.done && row.target.IsZero() {
+			row.togglePause()
+		}
+	case kb.RestartKey:
+		if row != nil && row.target.IsZero() {
+			row.restart()
+		}
+	case kb.DeleteKey:
+		m.deleteFocused()
+	case "k":
+		m.moveFocus(-1)
+	case "j":
+		m.moveFocus(1)
+	case "n", "a":
+		m.input.SetValue("")
+		m.input.Focus()
+		m.adding = true
+	}
+}
+
+func (m dashboardModel) controlsLine() string {
+	kb := m.payload.Config.Keybindings
+	return fmt.Sprintf("Controls: ↑/↓ Select | %s/%s Pause-Resume | %s Restart | %s Delete | n Add | %s/%s/Ctrl+C Exit",
+		keyTokenLabel(kb.PauseKey), keyTokenLabel(kb.PauseAltKey), keyTokenLabel(kb.RestartKey),
+		keyTokenLabel(kb.DeleteKey), keyTokenLabel(kb.ExitKey), keyTokenLabel(kb.ExitAltKey))
+}
+
+func (m dashboardModel) tableLines() []string {
+	headers := []string{"NAME", "KIND", "TIME", "PROGRESS", "STATE"}
+	cells := [][]string{headers}
+	for _, row := range m.rows {
+		cells = append(cells, []string{
+			row.name,
+			row.kind(),
+			formatClock(row.clock.displaySeconds(), m.payload.Config.DayFormat),
+			row.progressBar(),
+			row.stateName(),
+		})
+	}
+	widths := make([]int, len(headers))
+	for _, line := range cells {
+		for col, cell := range line {
+			if w := lipgloss.Width(cell); w > widths[col] {
+				widths[col] = w
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(cells))
+	for idx, line := range cells {
+		padded := make([]string, len(line))
+		for col, cell := range line {
+			padded[col] = cell + strings.Repeat(" ", widths[col]-lipgloss.Width(cell))
+		}
+		text := clipLine(strings.TrimRight("  "+strings.Join(padded, "   "), " "), m.width)
+		switch {
+		case idx == 0:
+		case idx-1 == m.focus:
+			text = dashboardFocusStyle.Render(clipLine("> "+strings.Join(padded, "   "), m.width))
+		case m.rows[idx-1].clock.done:
+			text = dashboardDoneStyle.Render(text)
+		}
+		lines = append(lines, text)
+	}
+	return lines
+}
+
+func (m dashboardModel) View() string {
+	cfg := m.payload.Config
+	var lines []string
+	if cfg.ShowHeader {
+		lines = append(lines, clipLine(fmt.Sprintf("Dashboard | %s", pluralize(len(m.rows), "timer")), m.width))
+	}
+	if cfg.ShowControls {
+		lines = append(lines, clipLine(m.controlsLine(), m.width))
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+
+	if len(m.rows) == 0 {
+		lines = append(lines, "No timers yet. Press n to add one, e.g. tea 4 min.")
+	} else {
+		lines = append(lines, m.tableLines()...)
+		if row := m.rows[m.focus]; row.clock.done {
+			lines = append(lines, "", fmt.Sprintf("%s: %s", row.name, cfg.CompletionMessage))
+		}
+	}
+
+	if m.adding {
+		lines = append(lines, "", m.input.View(),
+			wrapText("Name and duration, such as tea 4 min or standup until 10:00; a name alone starts a preset or a stopwatch. Enter adds, Esc cancels.", m.width))
+	}
+	if m.err != nil {
+		lines = append(lines, "", fmt.Sprintf("Error: %v", m.err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runDashboardCommand runs `dashboard`, optionally starting with the rows
+// given to --add.
+func runDashboardCommand(args []string, configPath string, fontDir string, admin adminPolicy) int {
+	adds, args, err := extractFlag(args, "add")
+	var tagValues []string
+	if err == nil {
+		tagValues, args, err = extractFlag(args, "tag")
+	}
+	var tags []string
+	if err == nil {
+		tags, err = normalizeTags(tagValues)
+	}
+	if err == nil && len(args) > 0 {
+		err = fmt.Errorf("unexpected arguments: %s", strings.Join(args, " "))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Fprintln(os.Stderr, "The dashboard requires an interactive terminal (TTY).")
+		return 1
+	}
+	payload, err := loadStandalonePayload(configPath, fontDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	payload.Config = admin.apply(payload.Config)
+
+	m := newDashboardModel(payload)
+	m.tags = tags
+	for _, text := range adds {
+		if err := m.addTimer(text); err != nil {
+			fmt.Fprintf(os.Stderr, "--add %q: %v\n", text, err)
+			return 1
+		}
+	}
+	m.focus = 0
+	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Dashboard failed: %v\n", err)
+		return 1
+	}
+	if final, ok := final.(dashboardModel); ok {
+		recordSessions(payload, final.sessions()...)
+	}
+	return 0
+}
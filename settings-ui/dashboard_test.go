@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testDashboard(t *testing.T, timers ...string) (dashboardModel, *fakeClock) {
+	t.Helper()
+	fake := newFakeClock()
+	fake.t = time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	payload := testPayload()
+	payload.Config.Keybindings.DeleteKey = "d"
+	payload.Config.TimeZone = "UTC"
+	m := newDashboardModel(payload)
+	m.now = fake.now
+	for _, text := range timers {
+		if err := m.addTimer(text); err != nil {
+			t.Fatalf("adding %q: %v", text, err)
+		}
+	}
+	return m, fake
+}
+
+func TestParseDashboardTimer(t *testing.T) {
+	now := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	presets := []timerPreset{{Name: "tea", Duration: "4 min"}}
+	cases := []struct {
+		text      string
+		name      string
+		seconds   int
+		stopwatch bool
+	}{
+		{"laundry 45 min", "laundry", 45 * 60, false},
+		{"release v2 1h30m", "release v2", 90 * 60, false},
+		{"standup until 10:00", "standup", 3600, false},
+		{"TEA", "tea", 4 * 60, false},
+		{"25:00", "", 25 * 60, false},
+		{"build", "build", 0, true},
+	}
+	for _, tc := range cases {
+		name, spec, stopwatch, err := parseDashboardTimer(tc.text, presets, now)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.text, err)
+		}
+		if name != tc.name || stopwatch != tc.stopwatch || (!stopwatch && spec.Seconds(now) != tc.seconds) {
+			t.Fatalf("%q: got %q, %d seconds, stopwatch=%v", tc.text, name, spec.Seconds(now), stopwatch)
+		}
+	}
+	if _, _, _, err := parseDashboardTimer("   ", presets, now); err == nil {
+		t.Fatalf("expected empty text to be rejected")
+	}
+}
+
+func TestDashboardActsOnFocusedRow(t *testing.T) {
+	m, fake := testDashboard(t, "tea 4 min", "laundry 45 min", "build")
+	if m.focus != 2 {
+		t.Fatalf("expected the last added row to be focused, got %d", m.focus)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	m = press(m, runeKey("p"))
+	fake.t = fake.t.Add(time.Minute)
+
+	if !m.rows[1].clock.paused || m.rows[0].clock.paused || m.rows[2].clock.paused {
+		t.Fatalf("expected only laundry to be paused")
+	}
+	if got := m.rows[0].clock.displaySeconds(); got != 3*60 {
+		t.Fatalf("expected tea to keep running, got %d seconds left", got)
+	}
+	if got := m.rows[2].clock.displaySeconds(); got != 60 {
+		t.Fatalf("expected the stopwatch to count up, got %d", got)
+	}
+
+	m = press(m, runeKey("k"))
+	m = press(m, runeKey("r"))
+	if got := m.rows[0].clock.displaySeconds(); got != 4*60 {
+		t.Fatalf("expected restart to reset tea, got %d", got)
+	}
+
+	m = press(m, runeKey("d"))
+	if len(m.rows) != 2 || m.rows[0].name != "laundry" || len(m.removed) != 1 {
+		t.Fatalf("expected tea to be deleted, got %d rows", len(m.rows))
+	}
+	if entries := m.sessions(); len(entries) != 3 || entries[0].Label != "tea" {
+		t.Fatalf("expected the deleted row to stay in the history, got %+v", entries)
+	}
+}
+
+func TestDashboardFinishesRowsIndependently(t *testing.T) {
+	m, fake := testDashboard(t, "egg 1 min", "bread 2 min")
+	fake.t = fake.t.Add(61 * time.Second)
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ := m.Update(clockTickMsg(fake.t))
+	m = updated.(dashboardModel)
+
+	if !m.rows[0].clock.done || m.rows[1].clock.done {
+		t.Fatalf("expected only the egg timer to be done")
+	}
+	if bar := m.rows[1].progressBar(); !strings.HasPrefix(bar, "[==========----------]  50%") {
+		t.Fatalf("unexpected progress bar %q", bar)
+	}
+	view := m.View()
+	for _, want := range []string{"Dashboard | 2 timers", "d Delete", "egg: Time is up!", "Done"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in view:\n%s", want, view)
+		}
+	}
+}
+
+func TestDashboardAddsTimersFromInput(t *testing.T) {
+	m, _ := testDashboard(t)
+	m = press(m, runeKey("n"))
+	if !m.adding {
+		t.Fatalf("expected n to open the input")
+	}
+	// The pause key is typed into the input rather than acting on a row.
+	m = press(m, runeKey("p"))
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.adding || len(m.rows) != 1 || m.rows[0].name != "p" || m.rows[0].clock.mode != "stopwatch" {
+		t.Fatalf("expected a stopwatch named p, got %+v", m.rows)
+	}
+
+	m, _ = testDashboard(t)
+	m = press(m, runeKey("n"))
+	for _, r := range "tea 4 min" {
+		m = press(m, runeKey(string(r)))
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.adding || len(m.rows) != 1 || m.rows[0].name != "tea" || m.rows[0].total != 240 {
+		t.Fatalf("expected a 4 minute tea timer, got %+v", m.rows)
+	}
+
+	m = press(m, runeKey("n"))
+	for _, r := range "standup until 8:00" {
+		m = press(m, runeKey(string(r)))
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.rows) != 2 || m.rows[1].clock.target.Hour() != 8 || m.rows[1].total != 23*3600 {
+		t.Fatalf("expected a past time of day to mean tomorrow, got %+v", m.rows[1])
+	}
+}
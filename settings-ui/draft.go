@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const draftFileName = "settings-draft.json"
+
+// draftPath is where unsaved changes are autosaved, next to the config so the
+// same write policy covers it.
+func draftPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), draftFileName)
+}
+
+// writeDraft autosaves the in-progress config. It is best effort: a draft
+// that cannot be written must not get in the way of editing.
+func (m *model) writeDraft() {
+	if m.payload.ConfigPath == "" {
+		return
+	}
+	_ = m.writePolicy.writeConfigFile(draftPath(m.payload.ConfigPath), m.payload.Config)
+}
+
+func (m *model) removeDraft() {
+	if m.payload.ConfigPath == "" {
+		return
+	}
+	_ = os.Remove(draftPath(m.payload.ConfigPath))
+}
+
+// loadNewerDraft returns the draft left by an earlier session when it is
+// newer than the config file (or the config was never saved).
+func loadNewerDraft(configPath string) (config, time.Time, bool) {
+	path := draftPath(configPath)
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return config{}, time.Time{}, false
+	}
+	if configInfo, err := os.Stat(configPath); err == nil && !info.ModTime().After(configInfo.ModTime()) {
+		return config{}, time.Time{}, false
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return config{}, time.Time{}, false
+	}
+	return cfg, info.ModTime(), true
+}
+
+// offerDraftRestore opens the restore prompt when a newer draft holds
+// changes. Drafts that match the loaded config are simply removed.
+func (m *model) offerDraftRestore() {
+	cfg, modTime, ok := loadNewerDraft(m.payload.ConfigPath)
+	if !ok {
+		return
+	}
+	payload := m.payload
+	payload.Config = cfg
+	cfg = m.admin.apply(finishPayload(payload).Config)
+	if reflect.DeepEqual(cfg, m.payload.Config) {
+		m.removeDraft()
+		return
+	}
+	m.draft = &cfg
+	m.draftTime = modTime
+	m.screen = screenRestoreDraft
+}
+
+func (m *model) restoreDraft() {
+	if m.draft != nil {
+		m.payload.Config = *m.draft
+		m.notice = "Restored unsaved changes from the last session (not saved yet)"
+	}
+	m.draft = nil
+	m.screen = screenMain
+	m.refreshMenu()
+}
+
+func (m *model) discardDraft() {
+	m.draft = nil
+	m.removeDraft()
+	m.screen = screenMain
+}
+
+func (m model) restoreDraftView(footer string) string {
+	if m.draft == nil {
+		return footer
+	}
+	changed := changedFields(m.payload.Config, *m.draft)
+	return fmt.Sprintf(
+		"Unsaved changes from %s were found.\n\nChanged: %s\n\nRestore them?\n\n%s",
+		m.draftTime.Format("2006-01-02 15:04"),
+		strings.Join(changed, ", "),
+		footer,
+	)
+}
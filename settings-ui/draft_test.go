@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func draftTestModel(t *testing.T) model {
+	t.Helper()
+	payload := testPayload()
+	payload.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	m := newModel(payload)
+	m.writePolicy = configWritePolicy{dir: filepath.Dir(payload.ConfigPath)}
+	return m
+}
+
+func TestChangesAreAutosavedToDraft(t *testing.T) {
+	m := draftTestModel(t)
+	m.jumpToMenuEntry("center")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+	draft, err := readConfigFile(draftPath(next.payload.ConfigPath))
+	if err != nil || draft.CenterDisplay != next.payload.Config.CenterDisplay || draft.CenterDisplay == m.payload.Config.CenterDisplay {
+		t.Fatalf("expected toggled value in draft, got %+v err=%v", draft, err)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if _, err := os.Stat(draftPath(next.payload.ConfigPath)); !os.IsNotExist(err) {
+		t.Fatalf("expected cancel to remove the draft, got %v", err)
+	}
+	if !updated.(model).cancelled {
+		t.Fatalf("expected cancel")
+	}
+}
+
+func TestSaveRemovesDraft(t *testing.T) {
+	m := draftTestModel(t)
+	m.jumpToMenuEntry("center")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.(model).Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	next := updated.(model)
+	if next.err != nil || !next.quitting {
+		t.Fatalf("expected save, err=%v", next.err)
+	}
+	if _, err := os.Stat(draftPath(next.payload.ConfigPath)); !os.IsNotExist(err) {
+		t.Fatalf("expected save to remove the draft, got %v", err)
+	}
+}
+
+func writeTestDraft(t *testing.T, m model, cfg config, age time.Duration) {
+	t.Helper()
+	if err := m.writePolicy.writeConfigFile(m.payload.ConfigPath, m.payload.Config); err != nil {
+		t.Fatal(err)
+	}
+	path := draftPath(m.payload.ConfigPath)
+	if err := m.writePolicy.writeConfigFile(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	stamp := time.Now().Add(age)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewerDraftOffersRestore(t *testing.T) {
+	m := draftTestModel(t)
+	cfg := m.payload.Config
+	cfg.TickRateMs = 250
+	writeTestDraft(t, m, cfg, time.Minute)
+
+	m.offerDraftRestore()
+	if m.screen != screenRestoreDraft || !strings.Contains(m.View(), "tickRateMs") {
+		t.Fatalf("expected restore prompt listing changes, got screen %v:\n%s", m.screen, m.View())
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	next := updated.(model)
+	if next.screen != screenMain || next.payload.Config.TickRateMs != 250 {
+		t.Fatalf("expected draft to be restored, got %d", next.payload.Config.TickRateMs)
+	}
+}
+
+func TestDeclinedDraftIsRemoved(t *testing.T) {
+	m := draftTestModel(t)
+	cfg := m.payload.Config
+	cfg.TickRateMs = 250
+	writeTestDraft(t, m, cfg, time.Minute)
+
+	m.offerDraftRestore()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	next := updated.(model)
+	if next.payload.Config.TickRateMs != m.payload.Config.TickRateMs {
+		t.Fatalf("expected draft to be discarded")
+	}
+	if _, err := os.Stat(draftPath(m.payload.ConfigPath)); !os.IsNotExist(err) {
+		t.Fatalf("expected discarded draft to be removed, got %v", err)
+	}
+}
+
+func TestStaleDraftIsIgnored(t *testing.T) {
+	m := draftTestModel(t)
+	cfg := m.payload.Config
+	cfg.TickRateMs = 250
+	writeTestDraft(t, m, cfg, -time.Hour)
+
+	m.offerDraftRestore()
+	if m.screen != screenMain {
+		t.Fatalf("expected a draft older than the config to be ignored")
+	}
+}
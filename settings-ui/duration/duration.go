@@ -0,0 +1,352 @@
+// Package duration parses the durations and targets given to `timer` on the
+// command line. The Go runtime and any headless commands share it so that
+// every entry point accepts the same forms:
+//
+//	5 min 2 sec            <number> <unit> pairs, as the JS timer takes
+//	1h30m  90s  1.5h       compact forms
+//	5:00  1:02:03          clock forms (m:ss and h:mm:ss)
+//	PT25M  P1DT2H          ISO-8601 durations
+//	until 17:30            absolute targets, see Parse
+//	at 9am tomorrow
+//
+// Forms can be mixed (`1h 30 min`). Errors are *ParseError values that name
+// the offending argument.
+package duration
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxDuration bounds relative durations; targets further out are fine.
+const MaxDuration = 365 * 24 * time.Hour
+
+var unitSeconds = map[string]float64{
+	"d":       86400,
+	"day":     86400,
+	"days":    86400,
+	"h":       3600,
+	"hr":      3600,
+	"hrs":     3600,
+	"hour":    3600,
+	"hours":   3600,
+	"m":       60,
+	"min":     60,
+	"mins":    60,
+	"minute":  60,
+	"minutes": 60,
+	"s":       1,
+	"sec":     1,
+	"secs":    1,
+	"second":  1,
+	"seconds": 1,
+}
+
+var (
+	numberPattern      = regexp.MustCompile(`^\d+(\.\d+)?$`)
+	compactPattern     = regexp.MustCompile(`^(\d+(\.\d+)?[a-z]+)+$`)
+	compactPartPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)([a-z]+)`)
+	clockPattern       = regexp.MustCompile(`^\d+(:\d{2}){1,2}$`)
+	isoPattern         = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	meridiemPattern    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
+	timeOfDayPattern   = regexp.MustCompile(`^(\d{1,2}):(\d{2})(?::(\d{2}))?$`)
+	datePattern        = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})(?:[Tt ](\d{1,2}):(\d{2})(?::(\d{2}))?)?$`)
+)
+
+// ParseError describes why the arguments could not be parsed.
+type ParseError struct {
+	Args []string
+	// Index is the offending argument, or -1 when the input as a whole is
+	// wrong (for example a zero total).
+	Index int
+	Msg   string
+}
+
+func (e *ParseError) Error() string {
+	if e.Index < 0 || e.Index >= len(e.Args) {
+		return e.Msg
+	}
+	return fmt.Sprintf("%s: %q (argument %d)", e.Msg, e.Args[e.Index], e.Index+1)
+}
+
+// Pointer returns the arguments on one line with carets under the offending
+// one, for printing below the error. It is empty when no argument is at
+// fault.
+func (e *ParseError) Pointer() string {
+	if e.Index < 0 || e.Index >= len(e.Args) {
+		return ""
+	}
+	offset := 0
+	for _, arg := range e.Args[:e.Index] {
+		offset += len([]rune(arg)) + 1
+	}
+	width := len([]rune(e.Args[e.Index]))
+	if width == 0 {
+		width = 1
+	}
+	return strings.Join(e.Args, " ") + "\n" + strings.Repeat(" ", offset) + strings.Repeat("^", width)
+}
+
+// Spec is a parsed duration: either a length of time or a wall-clock target.
+type Spec struct {
+	Duration time.Duration
+	Target   time.Time
+}
+
+// IsTarget reports whether the spec counts down to a point in time.
+func (s Spec) IsTarget() bool {
+	return !s.Target.IsZero()
+}
+
+// Remaining is how long is left at now.
+func (s Spec) Remaining(now time.Time) time.Duration {
+	if s.IsTarget() {
+		return s.Target.Sub(now)
+	}
+	return s.Duration
+}
+
+// Seconds is Remaining in whole seconds, rounded up so a timer never shows
+// zero before it is done.
+func (s Spec) Seconds(now time.Time) int {
+	remaining := s.Remaining(now)
+	if remaining <= 0 {
+		return 0
+	}
+	return int((remaining + time.Second - 1) / time.Second)
+}
+
+// Parse reads a duration or target from command line arguments. Targets
+// start with "until" or "at" and are resolved against now, in now's
+// location; pass now.In(loc) to use another time zone.
+func Parse(args []string, now time.Time) (Spec, error) {
+	if len(args) == 0 {
+		return Spec{}, &ParseError{Args: args, Index: -1, Msg: "missing duration"}
+	}
+	switch strings.ToLower(args[0]) {
+	case "until", "at":
+		target, err := parseTarget(args, now)
+		if err != nil {
+			return Spec{}, err
+		}
+		return Spec{Target: target}, nil
+	}
+	d, err := parseRelative(args)
+	if err != nil {
+		return Spec{}, err
+	}
+	return Spec{Duration: d}, nil
+}
+
+func parseRelative(args []string) (time.Duration, error) {
+	fail := func(idx int, msg string) (time.Duration, error) {
+		return 0, &ParseError{Args: args, Index: idx, Msg: msg}
+	}
+
+	total := 0.0
+	for idx := 0; idx < len(args); idx++ {
+		token := strings.ToLower(strings.TrimSpace(args[idx]))
+		switch {
+		case numberPattern.MatchString(token):
+			if idx+1 >= len(args) {
+				return fail(idx, "missing unit after number")
+			}
+			multiplier, ok := unitSeconds[strings.ToLower(strings.TrimSpace(args[idx+1]))]
+			if !ok {
+				return fail(idx+1, "unknown unit")
+			}
+			value, _ := strconv.ParseFloat(token, 64)
+			total += value * multiplier
+			idx++
+		case clockPattern.MatchString(token):
+			seconds, ok := parseClock(token)
+			if !ok {
+				return fail(idx, "minutes and seconds must be below 60")
+			}
+			total += seconds
+		case strings.HasPrefix(token, "p"):
+			seconds, ok := parseISO(strings.ToUpper(token))
+			if !ok {
+				return fail(idx, "invalid ISO-8601 duration (use weeks, days, hours, minutes and seconds, e.g. PT25M)")
+			}
+			total += seconds
+		case compactPattern.MatchString(token):
+			for _, part := range compactPartPattern.FindAllStringSubmatch(token, -1) {
+				multiplier, ok := unitSeconds[part[2]]
+				if !ok {
+					return fail(idx, fmt.Sprintf("unknown unit %q", part[2]))
+				}
+				value, _ := strconv.ParseFloat(part[1], 64)
+				total += value * multiplier
+			}
+		default:
+			if _, ok := unitSeconds[token]; ok {
+				return fail(idx, "unit without a number")
+			}
+			return fail(idx, "invalid duration")
+		}
+	}
+
+	if total > MaxDuration.Seconds() {
+		return fail(-1, "duration is longer than a year")
+	}
+	d := time.Duration(math.Round(total*1000)) * time.Millisecond
+	if d <= 0 {
+		return fail(-1, "total duration must be greater than zero")
+	}
+	return d, nil
+}
+
+// parseClock reads m:ss or h:mm:ss.
+func parseClock(token string) (float64, bool) {
+	parts := strings.Split(token, ":")
+	total := 0.0
+	for idx, part := range parts {
+		value, _ := strconv.Atoi(part)
+		if idx > 0 && value >= 60 {
+			return 0, false
+		}
+		total = total*60 + float64(value)
+	}
+	return total, true
+}
+
+func parseISO(token string) (float64, bool) {
+	match := isoPattern.FindStringSubmatch(token)
+	if match == nil || token == "P" || strings.HasSuffix(token, "T") {
+		return 0, false
+	}
+	total := 0.0
+	for idx, multiplier := range []float64{7 * 86400, 86400, 3600, 60, 1} {
+		if match[idx+1] != "" {
+			value, _ := strconv.ParseFloat(match[idx+1], 64)
+			total += value * multiplier
+		}
+	}
+	return total, true
+}
+
+type clockTime struct {
+	hour, minute, second int
+}
+
+// parseTimeOfDay reads 17:30, 17:30:15, 9am, 9:15pm, noon and midnight.
+func parseTimeOfDay(token string) (clockTime, bool) {
+	switch token {
+	case "noon":
+		return clockTime{hour: 12}, true
+	case "midnight":
+		return clockTime{}, true
+	}
+	if match := meridiemPattern.FindStringSubmatch(token); match != nil {
+		hour, _ := strconv.Atoi(match[1])
+		minute, _ := strconv.Atoi(match[2])
+		if hour < 1 || hour > 12 || minute > 59 {
+			return clockTime{}, false
+		}
+		hour %= 12
+		if match[3] == "pm" {
+			hour += 12
+		}
+		return clockTime{hour: hour, minute: minute}, true
+	}
+	if match := timeOfDayPattern.FindStringSubmatch(token); match != nil {
+		return makeClockTime(match[1], match[2], match[3])
+	}
+	return clockTime{}, false
+}
+
+func makeClockTime(hourText, minuteText, secondText string) (clockTime, bool) {
+	hour, _ := strconv.Atoi(hourText)
+	minute, _ := strconv.Atoi(minuteText)
+	second, _ := strconv.Atoi(secondText)
+	if hour > 23 || minute > 59 || second > 59 {
+		return clockTime{}, false
+	}
+	return clockTime{hour: hour, minute: minute, second: second}, true
+}
+
+// parseTarget resolves `until`/`at` arguments. A time of day without a day
+// that has already passed today means tomorrow.
+func parseTarget(args []string, now time.Time) (time.Time, error) {
+	fail := func(idx int, msg string) (time.Time, error) {
+		return time.Time{}, &ParseError{Args: args, Index: idx, Msg: msg}
+	}
+	if len(args) == 1 {
+		return fail(0, fmt.Sprintf("expected a time or date after %s", strings.ToLower(args[0])))
+	}
+
+	year, month, day := now.Date()
+	var clock clockTime
+	hasDate, hasTime, hasDay := false, false, false
+	for idx := 1; idx < len(args); idx++ {
+		token := strings.ToLower(strings.TrimSpace(args[idx]))
+		// "9 am" is two arguments.
+		if idx+1 < len(args) && numberPattern.MatchString(token) {
+			if next := strings.ToLower(args[idx+1]); next == "am" || next == "pm" {
+				token += next
+				idx++
+			}
+		}
+
+		if match := datePattern.FindStringSubmatch(token); match != nil {
+			if hasDate || hasDay {
+				return fail(idx, "date given twice")
+			}
+			y, _ := strconv.Atoi(match[1])
+			m, _ := strconv.Atoi(match[2])
+			d, _ := strconv.Atoi(match[3])
+			date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, now.Location())
+			if m < 1 || m > 12 || date.Day() != d {
+				return fail(idx, "invalid date")
+			}
+			year, month, day = y, time.Month(m), d
+			hasDate = true
+			if match[4] != "" {
+				if hasTime {
+					return fail(idx, "time given twice")
+				}
+				parsed, ok := makeClockTime(match[4], match[5], match[6])
+				if !ok {
+					return fail(idx, "invalid time")
+				}
+				clock, hasTime = parsed, true
+			}
+			continue
+		}
+
+		switch token {
+		case "today", "tomorrow":
+			if hasDate || hasDay {
+				return fail(idx, "date given twice")
+			}
+			hasDay = true
+			if token == "tomorrow" {
+				year, month, day = now.AddDate(0, 0, 1).Date()
+			}
+			continue
+		}
+
+		parsed, ok := parseTimeOfDay(token)
+		if !ok {
+			return fail(idx, "invalid time or date (use 17:30, 9am, tomorrow or 2026-12-31T23:59)")
+		}
+		if hasTime {
+			return fail(idx, "time given twice")
+		}
+		clock, hasTime = parsed, true
+	}
+
+	target := time.Date(year, month, day, clock.hour, clock.minute, clock.second, 0, now.Location())
+	if !target.After(now) && !hasDate && !hasDay {
+		target = time.Date(year, month, day+1, clock.hour, clock.minute, clock.second, 0, now.Location())
+	}
+	if !target.After(now) {
+		return fail(1, "target time is in the past")
+	}
+	return target, nil
+}
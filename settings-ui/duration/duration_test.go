@@ -0,0 +1,181 @@
+package duration
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Wednesday 2026-03-18 14:00 in Berlin.
+var testNow = time.Date(2026, 3, 18, 14, 0, 0, 0, mustLoadLocation("Europe/Berlin"))
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone("CET", 3600)
+	}
+	return loc
+}
+
+func TestParseDurations(t *testing.T) {
+	cases := []struct {
+		name string
+		args string
+		want time.Duration
+	}{
+		{"pair", "5 min", 5 * time.Minute},
+		{"pairs", "1 hr 30 MIN 15 sec", time.Hour + 30*time.Minute + 15*time.Second},
+		{"long unit", "2 hours", 2 * time.Hour},
+		{"decimal pair", "1.5 hr", 90 * time.Minute},
+		{"days", "1 day", 24 * time.Hour},
+		{"compact", "1h30m", 90 * time.Minute},
+		{"compact seconds", "90s", 90 * time.Second},
+		{"compact decimal", "1.5h", 90 * time.Minute},
+		{"compact long unit", "5min", 5 * time.Minute},
+		{"compact mixed case", "1H2M3S", time.Hour + 2*time.Minute + 3*time.Second},
+		{"clock m:ss", "5:00", 5 * time.Minute},
+		{"clock h:mm:ss", "1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"clock minutes past 60", "90:00", 90 * time.Minute},
+		{"iso minutes", "PT25M", 25 * time.Minute},
+		{"iso lower case", "pt1h30m", 90 * time.Minute},
+		{"iso days and hours", "P1DT2H", 26 * time.Hour},
+		{"iso weeks", "P1W", 7 * 24 * time.Hour},
+		{"iso fraction", "PT0.5S", 500 * time.Millisecond},
+		{"mixed forms", "1h 30 min", 90 * time.Minute},
+		{"mixed clock and pair", "1:00 30 sec", 90 * time.Second},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := Parse(strings.Fields(tc.args), testNow)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tc.args, err)
+			}
+			if spec.IsTarget() || spec.Duration != tc.want {
+				t.Fatalf("Parse(%q) = %+v, want %v", tc.args, spec, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTargets(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, testNow.Location())
+	}
+	cases := []struct {
+		name string
+		args string
+		want time.Time
+	}{
+		{"later today", "until 17:30", at(18, 17, 30)},
+		{"passed today rolls over", "until 9:15", at(19, 9, 15)},
+		{"meridiem", "at 9am", at(19, 9, 0)},
+		{"meridiem pm", "at 5:45pm", at(18, 17, 45)},
+		{"split meridiem", "at 9 PM", at(18, 21, 0)},
+		{"tomorrow", "at 9am tomorrow", at(19, 9, 0)},
+		{"tomorrow first", "until tomorrow 08:00", at(19, 8, 0)},
+		{"tomorrow alone", "until tomorrow", at(19, 0, 0)},
+		{"noon tomorrow", "until noon tomorrow", at(19, 12, 0)},
+		{"midnight", "until midnight", at(19, 0, 0)},
+		{"12am", "at 12am", at(19, 0, 0)},
+		{"12pm", "at 12pm tomorrow", at(19, 12, 0)},
+		{"seconds", "until 14:00:30", time.Date(2026, 3, 18, 14, 0, 30, 0, testNow.Location())},
+		{"iso date time", "until 2026-12-31T23:59", time.Date(2026, 12, 31, 23, 59, 0, 0, testNow.Location())},
+		{"date and time", "until 2026-12-31 23:59", time.Date(2026, 12, 31, 23, 59, 0, 0, testNow.Location())},
+		{"date only", "until 2026-04-01", time.Date(2026, 4, 1, 0, 0, 0, 0, testNow.Location())},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := Parse(strings.Fields(tc.args), testNow)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tc.args, err)
+			}
+			if !spec.IsTarget() || !spec.Target.Equal(tc.want) {
+				t.Fatalf("Parse(%q) = %v, want %v", tc.args, spec.Target, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseErrorsPointAtToken(t *testing.T) {
+	cases := []struct {
+		args  string
+		index int
+		msg   string
+	}{
+		{"", -1, "missing duration"},
+		{"5", 0, "missing unit after number"},
+		{"5 min 3", 2, "missing unit after number"},
+		{"1 days2", 1, "unknown unit"},
+		{"five min", 0, "invalid duration"},
+		{"-5 min", 0, "invalid duration"},
+		{"min", 0, "unit without a number"},
+		{"1h30x", 0, `unknown unit "x"`},
+		{"5:75", 0, "minutes and seconds must be below 60"},
+		{"1:5", 0, "invalid duration"},
+		{"P1M", 0, "invalid ISO-8601 duration"},
+		{"PT", 0, "invalid ISO-8601 duration"},
+		{"0 sec", -1, "total duration must be greater than zero"},
+		{"PT0S", -1, "total duration must be greater than zero"},
+		{"400 days", -1, "duration is longer than a year"},
+		{"until", 0, "expected a time or date after until"},
+		{"until 25:00", 1, "invalid time or date"},
+		{"at 13pm", 1, "invalid time or date"},
+		{"until soon", 1, "invalid time or date"},
+		{"until 17:30 18:00", 2, "time given twice"},
+		{"until today tomorrow", 2, "date given twice"},
+		{"until 2026-02-30", 1, "invalid date"},
+		{"until 2025-01-01", 1, "target time is in the past"},
+		{"until today 9:00", 1, "target time is in the past"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.args, func(t *testing.T) {
+			_, err := Parse(strings.Fields(tc.args), testNow)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse(%q) error = %v, want a *ParseError", tc.args, err)
+			}
+			if parseErr.Index != tc.index || !strings.HasPrefix(parseErr.Msg, tc.msg) {
+				t.Fatalf("Parse(%q) = index %d %q, want index %d %q", tc.args, parseErr.Index, parseErr.Msg, tc.index, tc.msg)
+			}
+		})
+	}
+}
+
+func TestParseErrorFormatting(t *testing.T) {
+	_, err := Parse([]string{"5", "min", "2", "parsecs"}, testNow)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a *ParseError, got %v", err)
+	}
+	if got := err.Error(); got != `unknown unit: "parsecs" (argument 4)` {
+		t.Fatalf("unexpected message %q", got)
+	}
+	want := "5 min 2 parsecs\n        ^^^^^^^"
+	if got := parseErr.Pointer(); got != want {
+		t.Fatalf("unexpected pointer:\n%s\nwant:\n%s", got, want)
+	}
+
+	_, err = Parse([]string{"0", "sec"}, testNow)
+	if errors.As(err, &parseErr); parseErr.Pointer() != "" || err.Error() != "total duration must be greater than zero" {
+		t.Fatalf("expected a whole-input error without a pointer, got %q", err.Error())
+	}
+}
+
+func TestSpecSecondsRoundsUp(t *testing.T) {
+	cases := []struct {
+		spec Spec
+		now  time.Time
+		want int
+	}{
+		{Spec{Duration: 90 * time.Second}, testNow, 90},
+		{Spec{Duration: 500 * time.Millisecond}, testNow, 1},
+		{Spec{Target: testNow.Add(10 * time.Second)}, testNow.Add(200 * time.Millisecond), 10},
+		{Spec{Target: testNow}, testNow.Add(time.Second), 0},
+	}
+	for _, tc := range cases {
+		if got := tc.spec.Seconds(tc.now); got != tc.want {
+			t.Fatalf("Seconds() for %+v = %d, want %d", tc.spec, got, tc.want)
+		}
+	}
+}
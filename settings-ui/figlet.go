@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// figletFont is a parsed FIGlet (.flf) font. Only the printable ASCII range
+// is loaded; that covers everything the timer draws.
+type figletFont struct {
+	height int
+	glyphs map[rune][]string
+}
+
+func parseFigletFont(text string) (*figletFont, error) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if !scanner.Scan() {
+		return nil, errors.New("empty font file")
+	}
+	header := strings.Fields(scanner.Text())
+	if len(header) < 6 || !strings.HasPrefix(header[0], "flf2a") || len(header[0]) < 6 {
+		return nil, errors.New("not a FIGlet font")
+	}
+	hardblank := []rune(header[0])[5]
+	height, err := strconv.Atoi(header[1])
+	if err != nil || height <= 0 {
+		return nil, fmt.Errorf("invalid font height %q", header[1])
+	}
+	comments, err := strconv.Atoi(header[5])
+	if err != nil || comments < 0 {
+		return nil, fmt.Errorf("invalid comment line count %q", header[5])
+	}
+	for i := 0; i < comments; i++ {
+		if !scanner.Scan() {
+			return nil, errors.New("font ends inside its comment block")
+		}
+	}
+
+	font := &figletFont{height: height, glyphs: map[rune][]string{}}
+	for ch := rune(32); ch <= 126; ch++ {
+		lines := make([]string, 0, height)
+		for row := 0; row < height; row++ {
+			if !scanner.Scan() {
+				if ch == 32 {
+					return nil, errors.New("font has no glyphs")
+				}
+				return font, nil
+			}
+			lines = append(lines, trimEndmark(scanner.Text(), hardblank))
+		}
+		font.glyphs[ch] = lines
+	}
+	return font, scanner.Err()
+}
+
+func trimEndmark(line string, hardblank rune) string {
+	line = strings.TrimRight(line, "\r")
+	if line == "" {
+		return line
+	}
+	endmark := line[len(line)-1:]
+	line = strings.TrimRight(line, endmark)
+	return strings.ReplaceAll(line, string(hardblank), " ")
+}
+
+// render draws text with "fitted" horizontal layout, the same mode the JS
+// timer asks figlet for: each glyph slides left until it would touch the
+// previous one. Runes missing from the font yield ok == false.
+func (f *figletFont) render(text string) (lines []string, ok bool) {
+	lines = make([]string, f.height)
+	for _, ch := range text {
+		glyph, found := f.glyphs[ch]
+		if !found {
+			return nil, false
+		}
+		overlap := -1
+		if !hasVisibleGlyphs(lines) {
+			overlap = 0
+		}
+		for row := 0; overlap != 0 && row < f.height; row++ {
+			trailing := len(lines[row]) - len(strings.TrimRight(lines[row], " "))
+			leading := len(glyph[row]) - len(strings.TrimLeft(glyph[row], " "))
+			if strings.TrimSpace(glyph[row]) == "" {
+				leading = len(glyph[row])
+			}
+			if gap := trailing + leading; overlap < 0 || gap < overlap {
+				overlap = gap
+			}
+		}
+		for row := 0; row < f.height; row++ {
+			cut := overlap
+			trimmed := strings.TrimRight(lines[row], " ")
+			trailing := len(lines[row]) - len(trimmed)
+			if cut <= trailing {
+				lines[row] = lines[row][:len(lines[row])-cut] + glyph[row]
+				continue
+			}
+			lines[row] = trimmed + glyph[row][cut-trailing:]
+		}
+	}
+	for row := range lines {
+		lines[row] = strings.TrimRight(lines[row], " ")
+	}
+	return lines, true
+}
+
+// timeCharFallbacks lists, per clock character, the glyphs tried first when a
+// font lacks it; every other printable ASCII glyph is tried after these. It
+// mirrors TIME_CHAR_FALLBACKS on the JS side.
+var timeCharFallbacks = map[rune]string{
+	'0': "0OoQDUX",
+	'1': "1Il|!TX",
+	'2': "2ZzSsX",
+	'3': "3EeBbX",
+	'4': "4AaHhX",
+	'5': "5Ss$X",
+	'6': "6GgbX",
+	'7': "7TtYyX",
+	'8': "8BbX",
+	'9': "9gqPpX",
+	':': ":|!iI.;X",
+}
+
+// syntheticFillChars are used to redraw a default-font glyph when a font has
+// nothing usable for a character; each font gets its own fill.
+const syntheticFillChars = "#@%&*+=~^$?"
+
+// fontGlyph is one character drawn on its own, padded to width when glyphs
+// are placed side by side.
+type fontGlyph struct {
+	lines []string
+	width int
+}
+
+func newFontGlyph(lines []string) *fontGlyph {
+	glyph := &fontGlyph{lines: lines}
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line); n > glyph.width {
+			glyph.width = n
+		}
+	}
+	return glyph
+}
+
+// fontLibrary loads fonts lazily from a figlet fonts directory and caches
+// them, their per-character glyphs and their clock sizes, including
+// failures, for the life of the process.
+type fontLibrary struct {
+	dir    string
+	fonts  map[string]*figletFont
+	glyphs map[string]*fontGlyph
+	sizes  map[string]fontMetrics
+}
+
+func newFontLibrary(dir string) *fontLibrary {
+	return &fontLibrary{
+		dir:    dir,
+		fonts:  map[string]*figletFont{},
+		glyphs: map[string]*fontGlyph{},
+		sizes:  map[string]fontMetrics{},
+	}
+}
+
+func (l *fontLibrary) font(name string) *figletFont {
+	if l == nil || l.dir == "" {
+		return nil
+	}
+	if font, ok := l.fonts[name]; ok {
+		return font
+	}
+	var font *figletFont
+	text, err := os.ReadFile(filepath.Join(l.dir, name+".flf"))
+	if err == nil {
+		font, _ = parseFigletFont(string(text))
+	}
+	l.fonts[name] = font
+	return font
+}
+
+func hasVisibleGlyphs(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlainRender reports whether a font drew text as the bare characters,
+// as terminal-style fonts do; that is no better than no font at all.
+func isPlainRender(lines []string, text string) bool {
+	var significant []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			significant = append(significant, line)
+		}
+	}
+	return len(significant) == 1 && significant[0] == strings.TrimSpace(text)
+}
+
+func (l *fontLibrary) renderWhole(text string, name string) ([]string, bool) {
+	font := l.font(name)
+	if font == nil {
+		return nil, false
+	}
+	lines, ok := font.render(text)
+	if !ok || !hasVisibleGlyphs(lines) || isPlainRender(lines, text) {
+		return nil, false
+	}
+	return lines, true
+}
+
+// glyph finds a drawing for ch in the named font: its own glyph, or else the
+// first usable substitute from timeCharFallbacks and the printable ASCII
+// range. It returns nil when the font has nothing usable.
+func (l *fontLibrary) glyph(name string, ch rune) *fontGlyph {
+	if l == nil {
+		return nil
+	}
+	cacheKey := name + "\x00" + string(ch)
+	if glyph, ok := l.glyphs[cacheKey]; ok {
+		return glyph
+	}
+	candidates := []rune(timeCharFallbacks[ch])
+	if len(candidates) == 0 {
+		candidates = []rune{ch}
+	}
+	for candidate := rune(33); candidate <= 126; candidate++ {
+		candidates = append(candidates, candidate)
+	}
+	var glyph *fontGlyph
+	for _, candidate := range candidates {
+		if lines, ok := l.renderWhole(string(candidate), name); ok {
+			glyph = newFontGlyph(lines)
+			break
+		}
+	}
+	l.glyphs[cacheKey] = glyph
+	return glyph
+}
+
+// synthesizeGlyph redraws the default font's glyph for ch with a fill
+// character picked from the font name, so a font without usable digits
+// still gets a clock that looks like its own.
+func (l *fontLibrary) synthesizeGlyph(name string, ch rune) *fontGlyph {
+	baseline := l.glyph(defaultFont, ch)
+	if baseline == nil {
+		return nil
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	fill := string(syntheticFillChars[hash.Sum32()%uint32(len(syntheticFillChars))])
+	lines := make([]string, len(baseline.lines))
+	for row, line := range baseline.lines {
+		var b strings.Builder
+		for _, r := range line {
+			if unicode.IsSpace(r) {
+				b.WriteRune(r)
+			} else {
+				b.WriteString(fill)
+			}
+		}
+		lines[row] = b.String()
+	}
+	return newFontGlyph(lines)
+}
+
+// renderByGlyphs draws text one character at a time, substituting or
+// synthesizing glyphs the font lacks, and places them bottom-aligned with a
+// space between them. It mirrors renderTimeByGlyphs on the JS side.
+func (l *fontLibrary) renderByGlyphs(text string, name string) ([]string, bool) {
+	var glyphs []*fontGlyph
+	for _, ch := range text {
+		glyph := l.glyph(name, ch)
+		if glyph == nil && name != defaultFont {
+			glyph = l.synthesizeGlyph(name, ch)
+		}
+		if glyph == nil {
+			glyph = l.glyph(defaultFont, ch)
+		}
+		if glyph == nil {
+			return nil, false
+		}
+		glyphs = append(glyphs, glyph)
+	}
+
+	height := 0
+	for _, glyph := range glyphs {
+		if len(glyph.lines) > height {
+			height = len(glyph.lines)
+		}
+	}
+	lines := make([]string, height)
+	for row := range lines {
+		parts := make([]string, len(glyphs))
+		for idx, glyph := range glyphs {
+			line := ""
+			if source := row - (height - len(glyph.lines)); source >= 0 {
+				line = glyph.lines[source]
+			}
+			parts[idx] = line + strings.Repeat(" ", glyph.width-utf8.RuneCountInString(line))
+		}
+		lines[row] = strings.TrimRight(strings.Join(parts, " "), " ")
+	}
+	if !hasVisibleGlyphs(lines) {
+		return nil, false
+	}
+	return lines, true
+}
+
+// renderTime draws text in the named font, mirroring renderTimeAscii on the
+// JS side: the whole text in the font, then glyph by glyph with substitutes,
+// then the same two steps in the default font, and finally plain text.
+func (l *fontLibrary) renderTime(text string, name string) []string {
+	attempts := []func() ([]string, bool){
+		func() ([]string, bool) { return l.renderWhole(text, name) },
+		func() ([]string, bool) { return l.renderByGlyphs(text, name) },
+		func() ([]string, bool) { return l.renderWhole(text, defaultFont) },
+		func() ([]string, bool) { return l.renderByGlyphs(text, defaultFont) },
+	}
+	for idx, attempt := range attempts {
+		lines, ok := attempt()
+		if !ok || (idx < len(attempts)-1 && isPlainRender(lines, text)) {
+			continue
+		}
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		return lines
+	}
+	return []string{text}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTestFont writes a FIGlet font covering space through last, drawing
+// each character with glyph.
+func writeTestFont(t *testing.T, dir string, name string, height int, last rune, glyph func(ch rune) []string) {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("flf2a$ " + string(rune('0'+height)) + " " + string(rune('0'+height)) + " 4 -1 0\n")
+	for ch := rune(32); ch <= last; ch++ {
+		lines := glyph(ch)
+		for row := 0; row < height; row++ {
+			line := ""
+			if row < len(lines) {
+				line = lines[row]
+			}
+			b.WriteString(strings.ReplaceAll(line, " ", "$") + "@\n")
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".flf"), []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseAndRenderFigletFont(t *testing.T) {
+	fonts := newFontLibrary("testdata/fonts")
+	font := fonts.font("Mini")
+	if font == nil {
+		t.Fatalf("expected Mini test font to load")
+	}
+
+	lines, ok := font.render("12:3")
+	if !ok {
+		t.Fatalf("expected digits and colon to render")
+	}
+	want := []string{"1122.33", "1 2  3", "1122.33"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("unexpected render:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestRenderTimeFallsBackToPlainText(t *testing.T) {
+	fonts := newFontLibrary(t.TempDir())
+	lines := fonts.renderTime("00:01:00", "Missing")
+	if !reflect.DeepEqual(lines, []string{"00:01:00"}) {
+		t.Fatalf("expected plain text fallback, got %q", lines)
+	}
+}
+
+func TestRenderFrameMatchesJSLayout(t *testing.T) {
+	cfg := testPayload().Config
+	frame := renderFrame(newFontLibrary(""), frameSpec{
+		mode:    "timer",
+		seconds: 90,
+		state:   framePaused,
+		cfg:     cfg,
+		width:   40,
+		height:  10,
+	})
+	lines := strings.Split(frame, "\n")
+	if len(lines) != 10 {
+		t.Fatalf("expected 10 lines, got %d", len(lines))
+	}
+	if lines[0] != "Timer | Font: Standard" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "Controls: p/Spacebar Pause-Resume") || len(lines[1]) != 40 {
+		t.Fatalf("expected controls line clipped to width, got %q", lines[1])
+	}
+	if lines[5] != "                00:01:30" || lines[7] != "                Paused" {
+		t.Fatalf("expected centred clock and paused label, got:\n%s", frame)
+	}
+}
+
+func TestRenderTimeSubstitutesMissingGlyphs(t *testing.T) {
+	dir := t.TempDir()
+	// Digits 0 and 1 and a dot, but no colon.
+	writeTestFont(t, dir, "Partial", 2, '1', func(ch rune) []string {
+		switch ch {
+		case '0':
+			return []string{"00", "00"}
+		case '1':
+			return []string{"1", "1"}
+		case '.':
+			return []string{".", "."}
+		}
+		return nil
+	})
+
+	lines := newFontLibrary(dir).renderTime("1:0", "Partial")
+	want := []string{"1 . 00", "1 . 00"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("expected the colon drawn with the dot glyph, got:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestRenderTimeSynthesizesGlyphsForPlainFonts(t *testing.T) {
+	dir := t.TempDir()
+	standard, err := os.ReadFile("testdata/fonts/Mini.flf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, defaultFont+".flf"), standard, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A terminal-style font that draws every character as itself.
+	writeTestFont(t, dir, "Term", 1, 126, func(ch rune) []string {
+		if ch == ' ' || ch == '@' {
+			return nil
+		}
+		return []string{string(ch)}
+	})
+
+	lines := newFontLibrary(dir).renderTime("1:0", "Term")
+	want := []string{"**  * **", "*     *", "**  * **"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("expected Standard glyphs redrawn with the font's fill, got:\n%s", strings.Join(lines, "\n"))
+	}
+}
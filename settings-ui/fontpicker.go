@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type fontSortMode int
+
+const (
+	fontSortName fontSortMode = iota
+	fontSortHeight
+	fontSortWidth
+)
+
+var fontSortNames = []string{"name", "height", "width"}
+
+func parseFontSortMode(name string) fontSortMode {
+	for idx, candidate := range fontSortNames {
+		if candidate == name {
+			return fontSortMode(idx)
+		}
+	}
+	return fontSortName
+}
+
+// fontMetrics is the size of a clock drawn in a font, which is what matters
+// when picking one for a small terminal.
+type fontMetrics struct {
+	height int
+	width  int
+}
+
+// metrics renders the clock once per font; later calls, such as every
+// re-sort of the picker, read the cached size.
+func (l *fontLibrary) metrics(name string) fontMetrics {
+	if l != nil {
+		if metrics, ok := l.sizes[name]; ok {
+			return metrics
+		}
+	}
+	lines := l.renderTime(formatHms(0), name)
+	metrics := fontMetrics{height: len(lines)}
+	for _, line := range lines {
+		if n := len([]rune(line)); n > metrics.width {
+			metrics.width = n
+		}
+	}
+	if l != nil {
+		l.sizes[name] = metrics
+	}
+	return metrics
+}
+
+// normalizeFavoriteFonts trims and de-duplicates favorites, keeping the order
+// they were starred in.
+func normalizeFavoriteFonts(fonts []string) []string {
+	result := []string{}
+	for _, font := range fonts {
+		font = strings.TrimSpace(font)
+		if font != "" && !containsString(result, font) {
+			result = append(result, font)
+		}
+	}
+	return result
+}
+
+// sortFonts orders fonts by rendered size. Fonts arrive sorted by name from
+// both getAllFonts and listFonts, so the name mode and ties keep that order.
+func (m model) sortFonts(fonts []string, mode fontSortMode) []string {
+	sorted := append([]string(nil), fonts...)
+	if mode == fontSortName {
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := m.fonts.metrics(sorted[i]), m.fonts.metrics(sorted[j])
+		if mode == fontSortHeight {
+			return a.height < b.height
+		}
+		return a.width < b.width
+	})
+	return sorted
+}
+
+// fontItems lists the font picker entries: favorites pinned to the top, then
+// recently used fonts most recent first, then everything else in the chosen
+// sort order.
+func (m model) fontItems() []list.Item {
+	var favorites, recent, rest []string
+	for _, font := range m.payload.Fonts {
+		if containsString(m.payload.Config.FavoriteFonts, font) {
+			favorites = append(favorites, font)
+		}
+	}
+	for _, font := range m.recentFonts {
+		if containsString(m.payload.Fonts, font) && !containsString(favorites, font) {
+			recent = append(recent, font)
+		}
+	}
+	for _, font := range m.payload.Fonts {
+		if !containsString(favorites, font) && !containsString(recent, font) {
+			rest = append(rest, font)
+		}
+	}
+
+	items := make([]list.Item, 0, len(m.payload.Fonts))
+	add := func(fonts []string, favorite bool, recent bool) {
+		for _, font := range fonts {
+			entry := fontEntry{name: font, favorite: favorite, recent: recent}
+			if m.fontSort != fontSortName {
+				metrics := m.fonts.metrics(font)
+				entry.metrics = &metrics
+			}
+			items = append(items, entry)
+		}
+	}
+	add(m.sortFonts(favorites, m.fontSort), true, false)
+	add(recent, false, true)
+	add(m.sortFonts(rest, m.fontSort), false, false)
+	return items
+}
+
+// refreshFontItems rebuilds the picker and keeps the cursor on the same font.
+// An applied filter is re-run right away; filtering is synchronous work, and
+// waiting for the list's command would briefly show an empty picker.
+func (m *model) refreshFontItems() {
+	selected, _ := m.fontList.SelectedItem().(fontEntry)
+	cmd := m.fontList.SetItems(m.fontItems())
+	m.fontList = runFilterCmd(m.fontList, cmd)
+	if selected.name != "" {
+		m.selectFontItem(selected.name)
+	}
+}
+
+func (m *model) toggleFavoriteFont() {
+	entry, ok := m.fontList.SelectedItem().(fontEntry)
+	if !ok {
+		return
+	}
+	if m.admin.menuEntryLocked("favoriteFonts") {
+		m.notice = lockedNotice("Favorite fonts")
+		return
+	}
+	favorites := []string{}
+	for _, font := range m.payload.Config.FavoriteFonts {
+		if font != entry.name {
+			favorites = append(favorites, font)
+		}
+	}
+	if len(favorites) == len(m.payload.Config.FavoriteFonts) {
+		favorites = append(favorites, entry.name)
+		m.notice = fmt.Sprintf("Starred %s", entry.name)
+	} else {
+		m.notice = fmt.Sprintf("Unstarred %s", entry.name)
+	}
+	m.payload.Config.FavoriteFonts = favorites
+	m.refreshFontItems()
+	m.refreshMenu()
+}
+
+func (m *model) cycleFontSort() {
+	m.fontSort = (m.fontSort + 1) % fontSortMode(len(fontSortNames))
+	m.fontList.Title = fontPickerTitle(m.fontSort)
+	m.refreshFontItems()
+}
+
+func fontPickerTitle(mode fontSortMode) string {
+	return "Select Font (sorted by " + fontSortNames[mode] + ")"
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func fontPickerTestModel() model {
+	payload := testPayload()
+	payload.Fonts = []string{"Alpha", "Big", "Mini", "Standard"}
+	payload.FontDir = "testdata/fonts"
+	m := newModel(payload)
+	m.screen = screenFontPicker
+	return m
+}
+
+func fontNames(m model) []string {
+	var names []string
+	for _, item := range m.fontList.Items() {
+		names = append(names, item.(fontEntry).name)
+	}
+	return names
+}
+
+func TestFontItemsPinFavoritesThenRecent(t *testing.T) {
+	m := fontPickerTestModel()
+	m.payload.Config.FavoriteFonts = []string{"Standard", "Missing"}
+	m.recentFonts = []string{"Mini", "Standard"}
+	m.refreshFontItems()
+
+	if got := fontNames(m); !reflect.DeepEqual(got, []string{"Standard", "Mini", "Alpha", "Big"}) {
+		t.Fatalf("unexpected order %v", got)
+	}
+	first := m.fontList.Items()[0].(fontEntry)
+	if !first.favorite || !strings.HasPrefix(first.Title(), "★") {
+		t.Fatalf("expected starred favorite first, got %q", first.Title())
+	}
+	if second := m.fontList.Items()[1].(fontEntry); !second.recent {
+		t.Fatalf("expected recent font second")
+	}
+}
+
+func TestStarKeyTogglesFavorite(t *testing.T) {
+	m := fontPickerTestModel()
+	m.selectFontItem("Big")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	next := updated.(model)
+	if !reflect.DeepEqual(next.payload.Config.FavoriteFonts, []string{"Big"}) {
+		t.Fatalf("expected Big starred, got %v", next.payload.Config.FavoriteFonts)
+	}
+	if selected := next.fontList.SelectedItem().(fontEntry); selected.name != "Big" || fontNames(next)[0] != "Big" {
+		t.Fatalf("expected Big pinned and still selected, got %q", selected.name)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	if favorites := updated.(model).payload.Config.FavoriteFonts; len(favorites) != 0 {
+		t.Fatalf("expected Big unstarred, got %v", favorites)
+	}
+}
+
+func TestSortKeyCyclesSizeOrder(t *testing.T) {
+	m := fontPickerTestModel()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	next := updated.(model)
+	if next.fontSort != fontSortHeight || !strings.Contains(next.fontList.Title, "height") {
+		t.Fatalf("expected height sort, got %v %q", next.fontSort, next.fontList.Title)
+	}
+	// Only Mini exists in testdata; the others render as one line of text.
+	if got := fontNames(next); got[len(got)-1] != "Mini" {
+		t.Fatalf("expected the tallest font last, got %v", got)
+	}
+	if entry := next.fontList.Items()[0].(fontEntry); entry.metrics == nil || !strings.Contains(entry.Description(), "lines") {
+		t.Fatalf("expected size in description, got %q", entry.Description())
+	}
+
+	for i := 0; i < 2; i++ {
+		updated, _ = updated.(model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	}
+	if updated.(model).fontSort != fontSortName {
+		t.Fatalf("expected sort to cycle back to name")
+	}
+}
+
+func TestFavoriteFontsNormalized(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.FavoriteFonts = []string{" Big ", "Big", "", "Slant"}
+	if got := normalizeConfig(cfg).FavoriteFonts; !reflect.DeepEqual(got, []string{"Big", "Slant"}) {
+		t.Fatalf("unexpected favorites %v", got)
+	}
+}
+
+func TestFontMetricsAreCachedPerFont(t *testing.T) {
+	fonts := newFontLibrary("testdata/fonts")
+	first := fonts.metrics("Mini")
+	if first.height != 3 || first.width == 0 {
+		t.Fatalf("unexpected Mini metrics %+v", first)
+	}
+	// Later lookups must not render again, even once the fonts are gone.
+	fonts.dir = t.TempDir()
+	fonts.fonts = map[string]*figletFont{}
+	if again := fonts.metrics("Mini"); again != first {
+		t.Fatalf("expected cached metrics %+v, got %+v", first, again)
+	}
+}
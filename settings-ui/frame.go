@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type frameState int
+
+const (
+	frameRunning frameState = iota
+	framePaused
+	frameDone
+)
+
+var frameStateNames = []string{"Running", "Paused", "Done"}
+
+const (
+	fallbackFrameWidth  = 120
+	fallbackFrameHeight = 24
+)
+
+func formatHms(totalSeconds int) string {
+	if totalSeconds < 0 {
+		totalSeconds = 0
+	}
+	hours := totalSeconds / 3600
+	minutes := (totalSeconds % 3600) / 60
+	seconds := totalSeconds % 60
+	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+}
+
+func controlsHelpLine(kb keybindings) string {
+	pause := keyTokenLabel(kb.PauseKey) + "/" + keyTokenLabel(kb.PauseAltKey)
+	restart := keyTokenLabel(kb.RestartKey)
+	style := keyTokenLabel(kb.StyleKey)
+	exit := keyTokenLabel(kb.ExitKey) + "/" + keyTokenLabel(kb.ExitAltKey) + "/Ctrl+C"
+	return fmt.Sprintf("Controls: %s Pause-Resume | %s Restart | %s Random Style | %s Exit", pause, restart, style, exit)
+}
+
+// frameSpec describes one frame of the running clock, as drawFrame in
+// src/index.js receives it.
+type frameSpec struct {
+	mode    string
+	seconds int
+	state   frameState
+	cfg     config
+	width   int
+	height  int
+	// title replaces the mode name in the header, e.g. "Pomodoro | Focus 1/4".
+	title string
+	// subtitle follows the mode in the header, e.g. "until Thu 31 Dec ...".
+	subtitle string
+}
+
+func clipLine(line string, width int) string {
+	runes := []rune(line)
+	if width > 0 && len(runes) > width {
+		return string(runes[:width])
+	}
+	return line
+}
+
+// renderFrame lays out a clock frame exactly like drawFrame and
+// writeCenteredBlockWithTop: header and controls at the top, the clock
+// centred in the remaining space, lines clipped to the terminal.
+func renderFrame(fonts *fontLibrary, spec frameSpec) string {
+	width := spec.width
+	if width <= 0 {
+		width = fallbackFrameWidth
+	}
+	height := spec.height
+	if height <= 0 {
+		height = fallbackFrameHeight
+	}
+
+	var topLines []string
+	title := "Stopwatch"
+	if spec.mode == "timer" {
+		title = "Timer"
+	}
+	if spec.title != "" {
+		title = spec.title
+	}
+	if spec.subtitle != "" {
+		title += " " + spec.subtitle
+	}
+	if spec.cfg.ShowHeader {
+		topLines = append(topLines, fmt.Sprintf("%s | Font: %s", title, spec.cfg.Font))
+	}
+	if spec.cfg.ShowControls {
+		controls := controlsHelpLine(spec.cfg.Keybindings)
+		if spec.mode == "stopwatch" {
+			controls += fmt.Sprintf(" | %s Lap", keyTokenLabel(spec.cfg.Keybindings.LapKey))
+		}
+		topLines = append(topLines, controls)
+	}
+	if len(topLines) > 0 {
+		topLines = append(topLines, "")
+	}
+
+	centerLines := fonts.renderTime(formatClock(spec.seconds, spec.cfg.DayFormat), spec.cfg.Font)
+	switch spec.state {
+	case frameDone:
+		if spec.cfg.CompletionMessage != "" {
+			centerLines = append(centerLines, "", spec.cfg.CompletionMessage)
+		}
+	case framePaused:
+		centerLines = append(centerLines, "", "Paused")
+	}
+
+	var lines []string
+	if spec.cfg.CenterDisplay {
+		blockWidth := 0
+		for _, line := range centerLines {
+			if n := len([]rune(line)); n > blockWidth {
+				blockWidth = n
+			}
+		}
+		padLeft := (width - blockWidth) / 2
+		if padLeft < 0 {
+			padLeft = 0
+		}
+		available := height - len(topLines)
+		padTop := (available - len(centerLines)) / 2
+		if padTop < 0 {
+			padTop = 0
+		}
+		lines = append(lines, topLines...)
+		for i := 0; i < padTop; i++ {
+			lines = append(lines, "")
+		}
+		prefix := strings.Repeat(" ", padLeft)
+		for _, line := range centerLines {
+			lines = append(lines, prefix+line)
+		}
+	} else {
+		lines = append(append(lines, topLines...), centerLines...)
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	for idx, line := range lines {
+		lines[idx] = clipLine(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+var settingDetails = map[string]string{
+	"font": "The figlet font used to draw the digits. Fonts without digit glyphs get close substitutes, " +
+		"so any font works, but `timer style --compatible` lists the ones that render natively. " +
+		"In the picker, * stars a font so it stays at the top and s sorts by name or rendered size.",
+	"randomFavorites": "Limits the random style key and `timer style random` to your starred fonts. " +
+		"Has no effect until at least one font is starred in the font picker.",
+	"center": "Centers the clock horizontally and vertically in the terminal. When off, " +
+		"the clock is drawn from the top-left corner, which is handy for tmux panes and screen recordings.",
+	"header": "Shows a first line with the mode (Timer or Stopwatch) and the current font name.",
+	"controls": "Shows the controls line under the header, built from your current keybindings, " +
+		"e.g. \"Controls: p/Spacebar Pause-Resume | r Restart | f Random Style | q/e/Ctrl+C Exit\".",
+	"dayFormat": "How timers longer than a day are shown. Days past 24h splits them off (1d 06:00:00); " +
+		"hours only keeps counting hours (30:00:00) like older versions did.",
+	"timeZone": "The zone `timer until 17:30` and `timer at 9am` are read in, as an IANA name such as Europe/Berlin or America/New_York. " +
+		"Leave it empty to use the system zone. `--tz` on the command line overrides it for one timer.",
+	"presets": "Named timers you start with `timer preset <name>`, e.g. tea for 4 min or standup for 15 min. " +
+		"Each has a duration in any form `timer` accepts and can override the font and completion message. " +
+		"In the editor, a adds, Enter edits, x deletes and Shift+↑/Shift+↓ reorder.",
+	"planDir": "Where `timer plan <name>` looks for interval plans (name.yaml, name.yml or name.json). " +
+		"Leave it empty to use the plans directory next to the config file; ~ stands for your home directory. " +
+		"`timer plan` without a name lists the plans found there.",
+	"stats": "Totals for today, this week and this month, your current and longest streak of days with a session, " +
+		"and a heatmap of recent weeks. ←/→ switch the table below it between days, weeks and months. " +
+		"Sessions are read from history.jsonl next to the config file.",
+	"historyRetention": "How many days of sessions history.jsonl keeps (1-3650). Older sessions are dropped the next time one is recorded.",
+	"preview": "Shows the full timer frame at your current terminal size with the font, header, controls line and centering you have set. " +
+		"Flip between the running, paused and done states to check the pause label and completion message.",
+	"tickRate": "How often the timer wakes up to check the clock and redraw. The display only changes once per second, " +
+		"so lower values mostly make pause/resume feel snappier at the cost of more CPU wake-ups. " +
+		"100 ms is 10 checks per second; 1000 ms is gentlest on battery but can lag up to a second.",
+	"message": "Text shown under the clock when a timer reaches zero, and used as the body of the system notification. " +
+		"Up to 240 characters; newlines are flattened to spaces.",
+	"notify": "Sends a desktop notification when a timer finishes. macOS uses terminal-notifier when installed, " +
+		"otherwise AppleScript (osascript). Linux uses notify-send (needs a notification daemon), " +
+		"termux-notification or kdialog. Windows uses a PowerShell toast, falling back to a tray balloon.",
+	"sound": "Rings the terminal bell five times when a timer finishes. Whether that is audible depends on your terminal's bell settings.",
+	"keymapPreset": "Applies a complete set of timer keybindings at once. Built-in presets are Default, Vim-like, Emacs-like and Numpad; " +
+		"presets exported by you or your teammates are loaded from the keymaps directory next to the config file.",
+	"pauseKey":    "Pauses and resumes the running timer or stopwatch.",
+	"pauseAltKey": "A second key for pause/resume, so both a letter and the spacebar can work.",
+	"restartKey":  "Resets the timer to its full duration (or the stopwatch to zero) and starts it again.",
+	"styleKey": "Switches the running clock to a random figlet font and saves that font as your new default. " +
+		"Press it repeatedly to browse fonts while a timer is running.",
+	"exitKey":    "Leaves the timer or stopwatch. Ctrl+C always exits as well.",
+	"exitAltKey": "A second exit key.",
+	"lapKey":     "Records a lap in the stopwatch. The lap table shows each lap, the running total and how far it was off your best lap.",
+	"deleteKey":  "Removes the selected timer from the `timer dashboard` table.",
+	"pomodoroFocus": "How long each focus phase of `timer pomodoro` runs, in minutes (1-180). " +
+		"In the editor, ←/→ step 1 and Shift+←/Shift+→ step 5.",
+	"pomodoroShortBreak": "The break after each focus phase, in minutes, except the one that ends a set.",
+	"pomodoroLongBreak":  "The longer break after the last focus phase of a set, in minutes. The next focus phase starts a new set.",
+	"pomodoroCycles": "How many focus phases make a set; a long break follows the last one. " +
+		"The header shows where you are, e.g. \"Pomodoro | Focus 2/4\".",
+	"pomodoroAutoBreaks":   "Starts each break as soon as a focus phase ends. When off, the clock waits at zero until you press the pause key.",
+	"pomodoroAutoFocus":    "Starts the next focus phase as soon as a break ends. When off, the clock waits at zero until you press the pause key.",
+	"pomodoroFocusMessage": "Shown under the clock and in the system notification when a focus phase ends.",
+	"pomodoroShortMessage": "Shown under the clock and in the system notification when a short break ends.",
+	"pomodoroLongMessage":  "Shown under the clock and in the system notification when a long break ends.",
+	"exportKeymap":         "Writes the current keybindings to a named JSON preset file that teammates can drop into their own keymaps directory.",
+	"resetAll":             "Restores every setting on these tabs to its built-in default after a confirmation. Nothing is written until you save.",
+	"save":                 "Writes all changes to the config file and closes the settings UI.",
+	"cancel":               "Closes the settings UI without writing anything.",
+}
+
+var screenTitles = map[screen]string{
+	screenMain:               "Timer Settings",
+	screenFontPicker:         "Font picker",
+	screenKeyPicker:          "Key picker",
+	screenTickRateEditor:     "Tick rate editor",
+	screenMessageEditor:      "Text editor",
+	screenKeymapPresetPicker: "Keymap preset picker",
+	screenKeymapExport:       "Export keymap preset",
+	screenPalette:            "Command palette",
+	screenPreview:            "Timer preview",
+	screenRestoreDraft:       "Restore unsaved changes",
+	screenTimeZoneEditor:     "Time zone editor",
+	screenNumberEditor:       "Number editor",
+	screenTimerPresetList:    "Timer presets",
+	screenTimerPresetForm:    "Timer preset editor",
+	screenStats:              "Session statistics",
+}
+
+func settingDetail(id string) string {
+	if detail, ok := settingDetails[id]; ok {
+		return detail
+	}
+	return ""
+}
+
+func wrapText(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		if line == "" {
+			line = word
+		} else {
+			line += " " + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m model) activeList() (list.Model, bool) {
+	switch m.screen {
+	case screenMain:
+		return m.menu, true
+	case screenFontPicker:
+		return m.fontList, true
+	case screenKeyPicker:
+		return m.keyList, true
+	case screenKeymapPresetPicker:
+		return m.keymapList, true
+	case screenTimerPresetList:
+		return m.timerPresetList, true
+	default:
+		return list.Model{}, false
+	}
+}
+
+// helpGroups returns every key available on the current screen: the screen's
+// own actions first, followed by the navigation keys of its list, if any.
+func (m model) helpGroups() [][]key.Binding {
+	groups := [][]key.Binding{append(m.screenBindings(), withHelpDesc(m.helpBinding(), "close help"))}
+	if l, ok := m.activeList(); ok {
+		for _, group := range l.FullHelp() {
+			var bindings []key.Binding
+			for _, binding := range group {
+				if containsString(binding.Keys(), "?") {
+					continue
+				}
+				bindings = append(bindings, binding)
+			}
+			if len(bindings) > 0 {
+				groups = append(groups, bindings)
+			}
+		}
+	}
+	return groups
+}
+
+func (m model) helpOverlayView() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Help: %s\n\n", screenTitles[m.screen])
+
+	if m.screen == screenMain {
+		if selected, ok := m.menu.SelectedItem().(menuEntry); ok {
+			if detail := settingDetail(selected.id); detail != "" {
+				fmt.Fprintf(&b, "%s\n%s\n\n", selected.title, wrapText(detail, m.helpWidth()))
+			}
+		}
+	}
+
+	// One column per group, stacked, so nothing is cut off on narrow terminals.
+	for _, group := range m.helpGroups() {
+		b.WriteString(m.help.FullHelpView([][]key.Binding{group}))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(footerLine(withHelpDesc(m.helpBinding(), "close help")))
+	return b.String()
+}
+
+// helpBinding is the key that opens the overlay on the current screen. While
+// text is being typed only non-printable help keys (F1) are active.
+func (m model) helpBinding() key.Binding {
+	switch m.screen {
+	case screenTickRateEditor, screenMessageEditor, screenTimeZoneEditor, screenNumberEditor, screenTimerPresetForm, screenKeymapExport, screenPalette:
+		return m.keys.textHelp()
+	}
+	if l, ok := m.activeList(); ok && l.SettingFilter() {
+		return m.keys.textHelp()
+	}
+	return m.keys.Help
+}
+
+// backBinding is the key that leaves the current list screen. While a list
+// filter is being typed only non-printable back keys (Esc) are active.
+func (m model) backBinding() key.Binding {
+	if l, ok := m.activeList(); ok && l.SettingFilter() {
+		return m.keys.textBack()
+	}
+	return m.keys.Back
+}
+
+func (m model) helpWidth() int {
+	if m.help.Width > 0 {
+		return m.help.Width
+	}
+	return 80
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHelpOverlayShowsSelectedSettingDetail(t *testing.T) {
+	m := newModel(testPayload())
+	m.setSection(sectionAdvanced)
+	for idx, item := range m.menu.Items() {
+		if item.(menuEntry).id == "tickRate" {
+			m.menu.Select(idx)
+		}
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	next := updated.(model)
+	if !next.showHelp {
+		t.Fatalf("expected ? to open the help overlay")
+	}
+	view := next.View()
+	if !strings.Contains(view, "CPU") || !strings.Contains(view, "save and exit") {
+		t.Fatalf("expected tick rate detail and main keys in help, got:\n%s", view)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(model).showHelp {
+		t.Fatalf("expected esc to close the help overlay")
+	}
+}
+
+func TestQuestionMarkIsTypedInTextInputs(t *testing.T) {
+	m := newModel(testPayload())
+	m.messageInput.SetValue("")
+	m.messageInput.Focus()
+	m.screen = screenMessageEditor
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	next := updated.(model)
+	if next.showHelp || next.messageInput.Value() != "?" {
+		t.Fatalf("expected ? to be typed, showHelp=%v value=%q", next.showHelp, next.messageInput.Value())
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyF1})
+	if !updated.(model).showHelp {
+		t.Fatalf("expected F1 to open help in text inputs")
+	}
+}
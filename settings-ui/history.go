@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	historyFileName             = "history.jsonl"
+	defaultHistoryRetentionDays = 365
+	minHistoryRetentionDays     = 1
+	maxHistoryRetentionDays     = 3650
+)
+
+// historyEntry is one line of history.jsonl: a timer or stopwatch session
+// from start to finish or exit. The JS runtime writes the same lines.
+type historyEntry struct {
+	Mode  string `json:"mode"`
+	Label string `json:"label,omitempty"`
+	// Tags come from --tag and group sessions in `report`.
+	Tags  []string  `json:"tags,omitempty"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// PlannedSeconds is the timer's length; 0 for the stopwatch.
+	PlannedSeconds int `json:"plannedSeconds"`
+	// ActualSeconds is the time the clock ran, without pauses.
+	ActualSeconds int  `json:"actualSeconds"`
+	PausedSeconds int  `json:"pausedSeconds"`
+	Pauses        int  `json:"pauses"`
+	Completed     bool `json:"completed"`
+}
+
+func historyPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), historyFileName)
+}
+
+// sessionTracker records when a session started and how long it was
+// paused. It outlives clock restarts, so a restarted timer is one session.
+type sessionTracker struct {
+	started   time.Time
+	pausedAt  time.Time
+	pausedFor time.Duration
+	pauses    int
+}
+
+func (s *sessionTracker) pause(now time.Time) {
+	if s.pausedAt.IsZero() {
+		s.pausedAt = now
+		s.pauses++
+	}
+}
+
+func (s *sessionTracker) resume(now time.Time) {
+	if !s.pausedAt.IsZero() {
+		s.pausedFor += now.Sub(s.pausedAt)
+		s.pausedAt = time.Time{}
+	}
+}
+
+// historyEntry describes the clock's session so far. A finished timer ends
+// when it reached zero, not when it was closed.
+func (c clockModel) historyEntry(label string) historyEntry {
+	end := c.now()
+	if c.done && !c.doneAt.IsZero() {
+		end = c.doneAt
+	}
+	paused := c.session.pausedFor
+	if !c.session.pausedAt.IsZero() && end.After(c.session.pausedAt) {
+		paused += end.Sub(c.session.pausedAt)
+	}
+	actual := end.Sub(c.session.started) - paused
+	if actual < 0 {
+		actual = 0
+	}
+	entry := historyEntry{
+		Mode:          c.mode,
+		Label:         label,
+		Start:         c.session.started.Truncate(time.Second),
+		End:           end.Truncate(time.Second),
+		ActualSeconds: int(actual / time.Second),
+		PausedSeconds: int(paused / time.Second),
+		Pauses:        c.session.pauses,
+		Tags:          c.tags,
+		Completed:     c.done || c.mode == "stopwatch",
+	}
+	if c.mode == "timer" {
+		entry.PlannedSeconds = c.baseSeconds
+	}
+	return entry
+}
+
+// readHistory reads every well-formed line of the history file; a missing
+// file is an empty history. Broken lines (say, from a crash mid-write) are
+// skipped rather than failing the whole log.
+func readHistory(path string) ([]historyEntry, error) {
+	text, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []historyEntry
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry historyEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && !entry.Start.IsZero() {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func sanitizeHistoryRetention(days int) int {
+	return clampInt(days, minHistoryRetentionDays, maxHistoryRetentionDays, defaultHistoryRetentionDays)
+}
+
+// appendHistory adds entries to the history file. Entries that ended more
+// than retentionDays before now are dropped on the way, which rewrites the
+// file; otherwise the new lines are only appended.
+func appendHistory(path string, entries []historyEntry, retentionDays int, now time.Time) error {
+	existing, err := readHistory(path)
+	if err != nil {
+		return err
+	}
+	cutoff := now.AddDate(0, 0, -sanitizeHistoryRetention(retentionDays))
+	kept := make([]historyEntry, 0, len(existing))
+	for _, entry := range existing {
+		if !entry.End.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+
+	if len(kept) == len(existing) {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+		if err := writeHistoryLines(file, entries); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".history-*.jsonl")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeHistoryLines(tmp, append(kept, entries...)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func writeHistoryLines(file *os.File, entries []historyEntry) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	_, err := file.Write(buf.Bytes())
+	return err
+}
+
+// recordSessions logs finished runs next to the config. Sessions that never
+// ran are left out; failures are reported but do not change the exit code.
+func recordSessions(payload statePayload, entries ...historyEntry) {
+	if payload.ConfigPath == "" {
+		return
+	}
+	var ran []historyEntry
+	for _, entry := range entries {
+		if entry.ActualSeconds > 0 || entry.Completed && entry.PlannedSeconds > 0 {
+			ran = append(ran, entry)
+		}
+	}
+	if len(ran) == 0 {
+		return
+	}
+	err := appendHistory(historyPath(payload.ConfigPath), ran, payload.Config.HistoryRetentionDays, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write history: %v\n", err)
+	}
+}
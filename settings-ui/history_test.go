@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryEntryExcludesPausesAndEndsWhenDone(t *testing.T) {
+	c, fake := testClock(60)
+	fake.t = fake.t.Add(20 * time.Second)
+	c = press(c, runeKey(" "))
+	fake.t = fake.t.Add(15 * time.Second)
+	c = press(c, runeKey(" "))
+	fake.t = fake.t.Add(40 * time.Second)
+	updated, _ := c.Update(clockTickMsg(fake.t))
+	c = updated.(clockModel)
+	// Left on the done screen for a while before exiting.
+	fake.t = fake.t.Add(time.Minute)
+
+	entry := c.historyEntry("tea")
+	if !entry.Completed || entry.Label != "tea" || entry.PlannedSeconds != 60 {
+		t.Fatalf("expected a completed 60s timer labelled tea, got %+v", entry)
+	}
+	if entry.ActualSeconds != 60 || entry.PausedSeconds != 15 || entry.Pauses != 1 {
+		t.Fatalf("expected 60s run with one 15s pause, got %+v", entry)
+	}
+	if got := entry.End.Sub(entry.Start); got != 75*time.Second {
+		t.Fatalf("expected the session to end when the timer reached zero, lasted %v", got)
+	}
+}
+
+func TestLabelledTimerShowsLabelAndLogsTags(t *testing.T) {
+	c, fake := testClock(60)
+	c.title = "code review"
+	c.tags = []string{"work"}
+	if view := c.View(); !strings.Contains(view, "code review | Font:") {
+		t.Fatalf("expected the label in the header, got:\n%s", view)
+	}
+	fake.t = fake.t.Add(10 * time.Second)
+	if entry := c.historyEntry("code review"); entry.Label != "code review" || len(entry.Tags) != 1 || entry.Tags[0] != "work" {
+		t.Fatalf("expected label and tags in the entry, got %+v", entry)
+	}
+}
+
+func TestHistoryEntryForStoppedTimerIsIncomplete(t *testing.T) {
+	c, fake := testClock(300)
+	c = press(c, runeKey("r"))
+	fake.t = fake.t.Add(90 * time.Second)
+	c = press(c, runeKey(" "))
+	fake.t = fake.t.Add(30 * time.Second)
+
+	entry := c.historyEntry("")
+	if entry.Completed || entry.ActualSeconds != 90 || entry.PausedSeconds != 30 {
+		t.Fatalf("expected an incomplete 90s run still paused for 30s, got %+v", entry)
+	}
+}
+
+func TestAppendHistoryPrunesOldEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := historyEntry{Mode: "timer", Start: now.AddDate(0, 0, -40), End: now.AddDate(0, 0, -40), ActualSeconds: 60}
+	recent := historyEntry{Mode: "stopwatch", Start: now.AddDate(0, 0, -2), End: now.AddDate(0, 0, -2), ActualSeconds: 30}
+
+	if err := appendHistory(path, []historyEntry{old, recent}, 365, now); err != nil {
+		t.Fatal(err)
+	}
+	latest := historyEntry{Mode: "timer", Label: "tea", Start: now, End: now.Add(time.Minute), ActualSeconds: 60}
+	if err := appendHistory(path, []historyEntry{latest}, 30, now); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := readHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Mode != "stopwatch" || entries[1].Label != "tea" {
+		t.Fatalf("expected the 40 day old entry to be pruned, got %+v", entries)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected history to be private, got %v", perm)
+	}
+}
+
+func TestReadHistorySkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	text := strings.Join([]string{
+		`{"mode":"timer","start":"2026-03-01T09:00:00Z","end":"2026-03-01T09:25:00Z","plannedSeconds":1500,"actualSeconds":1500,"completed":true}`,
+		`{"mode":"timer","start":"2026-03-01T10:00`,
+		`not json`,
+		`{"mode":"stopwatch","start":"2026-03-02T09:00:00Z","end":"2026-03-02T09:01:00Z","actualSeconds":60,"completed":true}`,
+	}, "\n")
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := readHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ActualSeconds != 1500 || entries[1].Mode != "stopwatch" {
+		t.Fatalf("expected the two well-formed lines, got %+v", entries)
+	}
+	if entries, err := readHistory(filepath.Join(t.TempDir(), "missing.jsonl")); err != nil || entries != nil {
+		t.Fatalf("expected a missing file to be an empty history, got %v, %v", entries, err)
+	}
+}
+
+func TestPomodoroLogsEachPhase(t *testing.T) {
+	settings := defaultPomodoro
+	settings.AutoStartFocus = true
+	p, fake := testPomodoro(settings)
+	p = finishCurrentPhase(p, fake)
+	p = finishCurrentPhase(p, fake)
+
+	if len(p.sessions) != 2 {
+		t.Fatalf("expected focus and short break to be logged, got %+v", p.sessions)
+	}
+	if got := p.sessions[0]; got.Mode != "pomodoro" || got.Label != "Focus" || !got.Completed || got.ActualSeconds != 25*60 {
+		t.Fatalf("unexpected focus entry %+v", got)
+	}
+	if got := p.sessions[1]; got.Label != "Short break" || !got.Start.Equal(p.sessions[0].End) {
+		t.Fatalf("expected the break to start where focus ended, got %+v", got)
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// settingsKeys holds the settings UI's own navigation keys as stored in the
+// config file. Each action accepts several keys in Bubble Tea's key notation
+// ("ctrl+s", "esc", "q", ...).
+type settingsKeys struct {
+	Save  []string `json:"save"`
+	Back  []string `json:"back"`
+	Quit  []string `json:"quit"`
+	Help  []string `json:"help"`
+	Reset []string `json:"reset"`
+}
+
+// ctrl+s is kept for existing muscle memory; ctrl+o is the fallback for
+// terminals where ctrl+s is swallowed by XON/XOFF flow control.
+var defaultSettingsKeys = settingsKeys{
+	Save:  []string{"ctrl+s", "ctrl+o"},
+	Back:  []string{"esc", "q"},
+	Quit:  []string{"ctrl+c", "q"},
+	Help:  []string{"?", "f1"},
+	Reset: []string{"r", "delete"},
+}
+
+type settingsKeyMap struct {
+	Confirm      key.Binding
+	Save         key.Binding
+	Back         key.Binding
+	Quit         key.Binding
+	Filter       key.Binding
+	Help         key.Binding
+	NextSection  key.Binding
+	PrevSection  key.Binding
+	Palette      key.Binding
+	Move         key.Binding
+	NextState    key.Binding
+	PrevState    key.Binding
+	Increase     key.Binding
+	Decrease     key.Binding
+	IncreaseMore key.Binding
+	DecreaseMore key.Binding
+	Reset        key.Binding
+	Yes          key.Binding
+	No           key.Binding
+	Favorite     key.Binding
+	SortFonts    key.Binding
+	AddItem      key.Binding
+	DeleteItem   key.Binding
+	MoveItemUp   key.Binding
+	MoveItemDown key.Binding
+	NextField    key.Binding
+	PrevField    key.Binding
+}
+
+func validSettingsKey(value string) bool {
+	if value == "" || len(value) > 16 || strings.ContainsAny(value, " \t\r\n") {
+		return false
+	}
+	if value == "enter" || value == "/" {
+		return false
+	}
+	return utf8.ValidString(value)
+}
+
+func normalizeSettingsKeyList(values []string, fallback []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		normalized := strings.ToLower(strings.TrimSpace(value))
+		if !validSettingsKey(normalized) || containsString(result, normalized) {
+			continue
+		}
+		result = append(result, normalized)
+	}
+	if len(result) == 0 {
+		return append([]string{}, fallback...)
+	}
+	return result
+}
+
+func normalizeSettingsKeys(cfg settingsKeys) settingsKeys {
+	return settingsKeys{
+		Save:  normalizeSettingsKeyList(cfg.Save, defaultSettingsKeys.Save),
+		Back:  normalizeSettingsKeyList(cfg.Back, defaultSettingsKeys.Back),
+		Quit:  normalizeSettingsKeyList(cfg.Quit, defaultSettingsKeys.Quit),
+		Help:  normalizeSettingsKeyList(cfg.Help, defaultSettingsKeys.Help),
+		Reset: normalizeSettingsKeyList(cfg.Reset, defaultSettingsKeys.Reset),
+	}
+}
+
+// fixedSettingsKeys are keys the settings UI binds itself, mapped to what
+// they do. A configured save, back, quit, help or reset key may not take one
+// over, or Tab would save and quit instead of switching section.
+var fixedSettingsKeys = map[string]string{
+	"tab":       "next section",
+	"shift+tab": "previous section",
+	"ctrl+p":    "search all settings",
+	"ctrl+n":    "move",
+	"up":        "move",
+	"down":      "move",
+	"left":      "previous state",
+	"right":     "next state",
+}
+
+// resolveSettingsKeys drops configured keys that clash with a fixed key or
+// with an earlier action, in the order save, back, quit, help, reset. Back
+// and quit may share keys: they are never active on the same screen. An
+// action left without keys gets its defaults that are still free. The
+// dropped keys are described for the status line.
+func resolveSettingsKeys(cfg settingsKeys) (settingsKeys, []string) {
+	cfg = normalizeSettingsKeys(cfg)
+	actions := []struct {
+		name     string
+		keys     *[]string
+		fallback []string
+	}{
+		{"save", &cfg.Save, defaultSettingsKeys.Save},
+		{"back", &cfg.Back, defaultSettingsKeys.Back},
+		{"quit", &cfg.Quit, defaultSettingsKeys.Quit},
+		{"help", &cfg.Help, defaultSettingsKeys.Help},
+		{"reset", &cfg.Reset, defaultSettingsKeys.Reset},
+	}
+	owners := map[string]string{}
+	free := func(action string, value string) (string, bool) {
+		if use, ok := fixedSettingsKeys[value]; ok {
+			return use, false
+		}
+		owner, taken := owners[value]
+		if !taken || (owner == "back" && action == "quit") {
+			return "", true
+		}
+		return owner, false
+	}
+
+	var dropped []string
+	for _, action := range actions {
+		var kept []string
+		for _, value := range *action.keys {
+			if use, ok := free(action.name, value); !ok {
+				dropped = append(dropped, fmt.Sprintf("%s %s (used for %s)", action.name, settingsKeyLabel(value), use))
+				continue
+			}
+			kept = append(kept, value)
+		}
+		if len(kept) == 0 {
+			for _, value := range action.fallback {
+				if _, ok := free(action.name, value); ok {
+					kept = append(kept, value)
+				}
+			}
+		}
+		for _, value := range kept {
+			if _, taken := owners[value]; !taken {
+				owners[value] = action.name
+			}
+		}
+		*action.keys = kept
+	}
+	return cfg, dropped
+}
+
+func settingsKeyLabel(value string) string {
+	if strings.HasPrefix(value, "ctrl+") {
+		return "Ctrl+" + strings.ToUpper(strings.TrimPrefix(value, "ctrl+"))
+	}
+	if strings.HasPrefix(value, "alt+") {
+		return "Alt+" + strings.TrimPrefix(value, "alt+")
+	}
+	return value
+}
+
+func settingsKeysLabel(values []string) string {
+	labels := make([]string, 0, len(values))
+	for _, value := range values {
+		labels = append(labels, settingsKeyLabel(value))
+	}
+	return strings.Join(labels, "/")
+}
+
+func newBinding(keys []string, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(settingsKeysLabel(keys), desc))
+}
+
+func newSettingsKeyMap(cfg settingsKeys) settingsKeyMap {
+	cfg, _ = resolveSettingsKeys(cfg)
+	return settingsKeyMap{
+		Confirm:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "select/edit")),
+		Save:         newBinding(cfg.Save, "save and exit"),
+		Back:         newBinding(cfg.Back, "back"),
+		Quit:         newBinding(cfg.Quit, "cancel"),
+		Filter:       key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		Help:         newBinding(cfg.Help, "help"),
+		NextSection:  key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "next section")),
+		PrevSection:  key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("Shift+Tab", "previous section")),
+		Palette:      key.NewBinding(key.WithKeys("ctrl+p", "/"), key.WithHelp("Ctrl+P or /", "search all settings")),
+		Move:         key.NewBinding(key.WithKeys("up", "down", "ctrl+p", "ctrl+n"), key.WithHelp("↑/↓", "move")),
+		NextState:    key.NewBinding(key.WithKeys("right", "tab", "l"), key.WithHelp("→/Tab", "next state")),
+		PrevState:    key.NewBinding(key.WithKeys("left", "shift+tab", "h"), key.WithHelp("←/Shift+Tab", "previous state")),
+		Increase:     key.NewBinding(key.WithKeys("right", "up"), key.WithHelp("→", fmt.Sprintf("+%d ms", tickRateStepMs))),
+		Decrease:     key.NewBinding(key.WithKeys("left", "down"), key.WithHelp("←", fmt.Sprintf("-%d ms", tickRateStepMs))),
+		IncreaseMore: key.NewBinding(key.WithKeys("shift+right", "shift+up", "pgup"), key.WithHelp("Shift+→", fmt.Sprintf("+%d ms", tickRateLargeStepMs))),
+		Reset:        newBinding(cfg.Reset, "reset setting to default"),
+		Yes:          key.NewBinding(key.WithKeys("y", "enter"), key.WithHelp("y/Enter", "yes, reset")),
+		No:           key.NewBinding(key.WithKeys("n"), key.WithHelp("n/esc", "no")),
+		DecreaseMore: key.NewBinding(key.WithKeys("shift+left", "shift+down", "pgdown"), key.WithHelp("Shift+←", fmt.Sprintf("-%d ms", tickRateLargeStepMs))),
+		Favorite:     key.NewBinding(key.WithKeys("*"), key.WithHelp("*", "star/unstar")),
+		SortFonts:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort by name/height/width")),
+		AddItem:      key.NewBinding(key.WithKeys("a", "n"), key.WithHelp("a", "add")),
+		DeleteItem:   key.NewBinding(key.WithKeys("x", "delete"), key.WithHelp("x/Delete", "delete")),
+		MoveItemUp:   key.NewBinding(key.WithKeys("K", "shift+up"), key.WithHelp("Shift+↑", "move up")),
+		MoveItemDown: key.NewBinding(key.WithKeys("J", "shift+down"), key.WithHelp("Shift+↓", "move down")),
+		NextField:    key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("Tab/↓", "next field")),
+		PrevField:    key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("Shift+Tab/↑", "previous field")),
+	}
+}
+
+func nonPrintableKeys(values []string) []string {
+	var keys []string
+	for _, value := range values {
+		if utf8.RuneCountInString(value) > 1 {
+			keys = append(keys, value)
+		}
+	}
+	return keys
+}
+
+// textBack is the back binding used while a text input has focus: printable
+// keys such as "q" must reach the input instead of leaving the screen.
+func (k settingsKeyMap) textBack() key.Binding {
+	keys := nonPrintableKeys(k.Back.Keys())
+	if len(keys) == 0 {
+		keys = []string{"esc"}
+	}
+	return newBinding(keys, "back")
+}
+
+// textHelp is the help binding used while a text input has focus.
+func (k settingsKeyMap) textHelp() key.Binding {
+	keys := nonPrintableKeys(k.Help.Keys())
+	if len(keys) == 0 {
+		keys = []string{"f1"}
+	}
+	return newBinding(keys, "help")
+}
+
+func withHelpDesc(binding key.Binding, desc string) key.Binding {
+	binding.SetHelp(binding.Help().Key, desc)
+	return binding
+}
+
+func footerLine(bindings ...key.Binding) string {
+	parts := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		if !binding.Enabled() {
+			continue
+		}
+		help := binding.Help()
+		parts = append(parts, help.Key+": "+help.Desc)
+	}
+	return strings.Join(parts, " | ")
+}
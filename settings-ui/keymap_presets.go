@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+const customKeymapPresetName = "Custom"
+
+type keymapPreset struct {
+	Name        string      `json:"name"`
+	Keybindings keybindings `json:"keybindings"`
+}
+
+var builtinKeymapPresets = []keymapPreset{
+	{
+		Name:        "Default",
+		Keybindings: defaultKeybindings,
+	},
+	{
+		Name: "Vim-like",
+		Keybindings: keybindings{
+			PauseKey:    "space",
+			PauseAltKey: "i",
+			RestartKey:  "0",
+			StyleKey:    "n",
+			ExitKey:     "q",
+			ExitAltKey:  "z",
+			LapKey:      "l",
+			DeleteKey:   "d",
+		},
+	},
+	{
+		Name: "Emacs-like",
+		Keybindings: keybindings{
+			PauseKey:    "space",
+			PauseAltKey: "s",
+			RestartKey:  "a",
+			StyleKey:    "n",
+			ExitKey:     "x",
+			ExitAltKey:  "g",
+			LapKey:      "l",
+			DeleteKey:   "d",
+		},
+	},
+	{
+		Name: "Numpad",
+		Keybindings: keybindings{
+			PauseKey:    "5",
+			PauseAltKey: "0",
+			RestartKey:  "1",
+			StyleKey:    "2",
+			ExitKey:     "9",
+			ExitAltKey:  ".",
+			LapKey:      "+",
+			DeleteKey:   "-",
+		},
+	},
+}
+
+type keymapPresetEntry struct {
+	preset keymapPreset
+}
+
+func (k keymapPresetEntry) Title() string { return k.preset.Name }
+func (k keymapPresetEntry) Description() string {
+	kb := k.preset.Keybindings
+	return fmt.Sprintf(
+		"Pause %s/%s | Restart %s | Style %s | Exit %s/%s | Lap %s | Delete %s",
+		keyTokenLabel(kb.PauseKey),
+		keyTokenLabel(kb.PauseAltKey),
+		keyTokenLabel(kb.RestartKey),
+		keyTokenLabel(kb.StyleKey),
+		keyTokenLabel(kb.ExitKey),
+		keyTokenLabel(kb.ExitAltKey),
+		keyTokenLabel(kb.LapKey),
+		keyTokenLabel(kb.DeleteKey),
+	)
+}
+func (k keymapPresetEntry) FilterValue() string { return k.preset.Name }
+
+func buildKeymapPresetItems(presets []keymapPreset) []list.Item {
+	items := make([]list.Item, 0, len(presets))
+	for _, preset := range presets {
+		items = append(items, keymapPresetEntry{preset: preset})
+	}
+	return items
+}
+
+func matchKeymapPreset(presets []keymapPreset, kb keybindings) string {
+	for _, preset := range presets {
+		if preset.Keybindings == kb {
+			return preset.Name
+		}
+	}
+	return customKeymapPresetName
+}
+
+func keymapPresetDir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "keymaps")
+}
+
+func keymapPresetFileName(name string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, ch := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9':
+			b.WriteRune(ch)
+			lastDash = false
+		case !lastDash && b.Len() > 0:
+			b.WriteByte('-')
+			lastDash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return ""
+	}
+	return slug + ".json"
+}
+
+func validateKeymapPreset(preset keymapPreset) error {
+	if strings.TrimSpace(preset.Name) == "" {
+		return errors.New("preset name is missing")
+	}
+	if strings.EqualFold(strings.TrimSpace(preset.Name), customKeymapPresetName) {
+		return fmt.Errorf("%q is reserved", customKeymapPresetName)
+	}
+	if normalizeKeybindings(preset.Keybindings) != preset.Keybindings {
+		return errors.New("preset contains unsupported key tokens")
+	}
+	return nil
+}
+
+func readKeymapPresetFile(path string) (keymapPreset, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return keymapPreset{}, err
+	}
+	var preset keymapPreset
+	if err := json.Unmarshal(text, &preset); err != nil {
+		return keymapPreset{}, err
+	}
+	preset.Name = strings.TrimSpace(preset.Name)
+	if preset.Keybindings.LapKey == "" {
+		// Presets exported before the lap key existed.
+		preset.Keybindings.LapKey = defaultKeybindings.LapKey
+	}
+	if preset.Keybindings.DeleteKey == "" {
+		// Presets exported before the dashboard's delete key existed.
+		preset.Keybindings.DeleteKey = defaultKeybindings.DeleteKey
+	}
+	if err := validateKeymapPreset(preset); err != nil {
+		return keymapPreset{}, err
+	}
+	return preset, nil
+}
+
+// loadKeymapPresetFiles reads every *.json preset in dir. A missing directory
+// is not an error; unreadable or invalid files are skipped and reported.
+func loadKeymapPresetFiles(dir string) ([]keymapPreset, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	presets := make([]keymapPreset, 0, len(paths))
+	var invalid []string
+	for _, path := range paths {
+		preset, err := readKeymapPresetFile(path)
+		if err != nil {
+			invalid = append(invalid, filepath.Base(path))
+			continue
+		}
+		presets = append(presets, preset)
+	}
+	if len(invalid) > 0 {
+		return presets, fmt.Errorf("skipped invalid keymap presets: %s", strings.Join(invalid, ", "))
+	}
+	return presets, nil
+}
+
+func mergeKeymapPresets(builtin []keymapPreset, custom []keymapPreset) []keymapPreset {
+	result := make([]keymapPreset, 0, len(builtin)+len(custom))
+	seen := map[string]bool{}
+	for _, preset := range append(append([]keymapPreset{}, builtin...), custom...) {
+		key := strings.ToLower(preset.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, preset)
+	}
+	return result
+}
+
+// exportKeymapPreset writes kb as a new preset file. It refuses builtin
+// names, which would be hidden behind the builtin preset, and never
+// replaces an existing file.
+func exportKeymapPreset(dir string, name string, kb keybindings) (string, error) {
+	preset := keymapPreset{Name: strings.TrimSpace(name), Keybindings: kb}
+	if err := validateKeymapPreset(preset); err != nil {
+		return "", err
+	}
+	for _, builtin := range builtinKeymapPresets {
+		if strings.EqualFold(preset.Name, builtin.Name) {
+			return "", fmt.Errorf("%q is a builtin preset; choose another name", builtin.Name)
+		}
+	}
+	fileName := keymapPresetFileName(preset.Name)
+	if fileName == "" {
+		return "", errors.New("preset name must contain letters or digits")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	text, err := json.MarshalIndent(preset, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	text = append(text, '\n')
+	path := filepath.Join(dir, fileName)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("a keymap preset already exists at %s; choose another name or delete it first", path)
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(text); err != nil {
+		file.Close()
+		return "", err
+	}
+	return path, file.Close()
+}
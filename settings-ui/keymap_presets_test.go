@@ -0,0 +1,124 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBuiltinKeymapPresetsAreValid(t *testing.T) {
+	for _, preset := range builtinKeymapPresets {
+		if err := validateKeymapPreset(preset); err != nil {
+			t.Fatalf("preset %q is invalid: %v", preset.Name, err)
+		}
+	}
+}
+
+func TestMatchKeymapPresetReportsCustom(t *testing.T) {
+	if got := matchKeymapPreset(builtinKeymapPresets, defaultKeybindings); got != "Default" {
+		t.Fatalf("expected Default, got %q", got)
+	}
+	kb := defaultKeybindings
+	kb.RestartKey = "z"
+	if got := matchKeymapPreset(builtinKeymapPresets, kb); got != customKeymapPresetName {
+		t.Fatalf("expected %q, got %q", customKeymapPresetName, got)
+	}
+}
+
+func TestExportedKeymapPresetLoadsBack(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keymaps")
+	kb := defaultKeybindings
+	kb.StyleKey = "y"
+
+	path, err := exportKeymapPreset(dir, "Team Layout", kb)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if filepath.Base(path) != "team-layout.json" {
+		t.Fatalf("unexpected preset file name %q", filepath.Base(path))
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	presets, err := loadKeymapPresetFiles(dir)
+	if err == nil {
+		t.Fatalf("expected broken preset to be reported")
+	}
+	if len(presets) != 1 || presets[0].Name != "Team Layout" || presets[0].Keybindings != kb {
+		t.Fatalf("unexpected presets %+v", presets)
+	}
+}
+
+func TestExportKeymapPresetRefusesBuiltinNamesAndOverwrites(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keymaps")
+	if _, err := exportKeymapPreset(dir, "vim-LIKE", defaultKeybindings); err == nil {
+		t.Fatalf("expected a builtin preset name to be rejected")
+	}
+	if _, err := exportKeymapPreset(dir, "Team Layout", defaultKeybindings); err != nil {
+		t.Fatal(err)
+	}
+	kb := defaultKeybindings
+	kb.StyleKey = "y"
+	if _, err := exportKeymapPreset(dir, "team layout!", kb); err == nil {
+		t.Fatalf("expected an existing preset file to be kept")
+	}
+	presets, err := loadKeymapPresetFiles(dir)
+	if err != nil || len(presets) != 1 || presets[0].Keybindings != defaultKeybindings {
+		t.Fatalf("expected the first export to be unchanged, got %+v, %v", presets, err)
+	}
+}
+
+func TestKeymapPresetPickerAppliesPreset(t *testing.T) {
+	m := newModel(testPayload())
+	m.screen = screenKeymapPresetPicker
+	m.keymapList.Select(1)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+
+	if next.screen != screenMain {
+		t.Fatalf("expected return to main screen, got %v", next.screen)
+	}
+	if next.payload.Config.Keybindings != builtinKeymapPresets[1].Keybindings {
+		t.Fatalf("expected %s bindings, got %+v", builtinKeymapPresets[1].Name, next.payload.Config.Keybindings)
+	}
+}
+
+func TestKeyPickerRefusesKeyBoundToAnotherAction(t *testing.T) {
+	m := newModel(testPayload())
+	m.screen = screenKeyPicker
+	m.keyTarget = "lapKey"
+	for idx, item := range m.keyList.Items() {
+		if item.(keyEntry).token == "p" {
+			m.keyList.Select(idx)
+		}
+	}
+
+	next := press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if next.screen != screenKeyPicker || next.err == nil || !strings.Contains(next.err.Error(), "p is already the pause key") {
+		t.Fatalf("expected the pause key to be refused, got screen %v err %v", next.screen, next.err)
+	}
+	if next.payload.Config.Keybindings.LapKey != defaultKeybindings.LapKey {
+		t.Fatalf("expected the lap key to stay %q, got %q", defaultKeybindings.LapKey, next.payload.Config.Keybindings.LapKey)
+	}
+}
+
+func TestResettingKeyRefusesClash(t *testing.T) {
+	m := newModel(testPayload())
+	m.payload.Config.Keybindings.PauseKey = "x"
+	m.payload.Config.Keybindings.ExitKey = "p"
+	if m.resetSetting("pauseKey") || m.err == nil {
+		t.Fatalf("expected resetting the pause key onto the exit key to fail")
+	}
+	if m.payload.Config.Keybindings.PauseKey != "x" {
+		t.Fatalf("expected the pause key to be kept, got %q", m.payload.Config.Keybindings.PauseKey)
+	}
+	m.resetAll()
+	if m.payload.Config.Keybindings != defaultKeybindings {
+		t.Fatalf("expected reset all to restore every key, got %+v", m.payload.Config.Keybindings)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNormalizeSettingsKeysFallsBackToDefaults(t *testing.T) {
+	got := normalizeSettingsKeys(settingsKeys{
+		Save: []string{" Ctrl+W ", "ctrl+w", "enter"},
+		Back: []string{"", "has space"},
+	})
+	if !reflect.DeepEqual(got.Save, []string{"ctrl+w"}) {
+		t.Fatalf("unexpected save keys %v", got.Save)
+	}
+	if !reflect.DeepEqual(got.Back, defaultSettingsKeys.Back) {
+		t.Fatalf("expected default back keys, got %v", got.Back)
+	}
+	if !reflect.DeepEqual(got.Quit, defaultSettingsKeys.Quit) {
+		t.Fatalf("expected default quit keys, got %v", got.Quit)
+	}
+}
+
+func TestResolveSettingsKeysDropsClashingKeys(t *testing.T) {
+	got, dropped := resolveSettingsKeys(settingsKeys{
+		Save:  []string{"tab", "ctrl+w"},
+		Back:  []string{"esc", "q"},
+		Quit:  []string{"q", "ctrl+w"},
+		Help:  []string{"up"},
+		Reset: []string{"?"},
+	})
+	if !reflect.DeepEqual(got.Save, []string{"ctrl+w"}) {
+		t.Fatalf("expected tab to stay next section, got save keys %v", got.Save)
+	}
+	if !reflect.DeepEqual(got.Quit, []string{"q"}) {
+		t.Fatalf("expected q shared with back and ctrl+w left to save, got %v", got.Quit)
+	}
+	if !reflect.DeepEqual(got.Help, defaultSettingsKeys.Help) {
+		t.Fatalf("expected help to fall back to its defaults, got %v", got.Help)
+	}
+	if !reflect.DeepEqual(got.Reset, []string{"r", "delete"}) {
+		t.Fatalf("expected ? left to help and reset to fall back, got %v", got.Reset)
+	}
+	want := []string{"save tab (used for next section)", "quit Ctrl+W (used for save)", "help up (used for move)", "reset ? (used for help)"}
+	if !reflect.DeepEqual(dropped, want) {
+		t.Fatalf("unexpected report %q", dropped)
+	}
+	if _, dropped := resolveSettingsKeys(defaultSettingsKeys); len(dropped) != 0 {
+		t.Fatalf("expected the defaults not to clash, got %q", dropped)
+	}
+}
+
+func TestClashingSettingsKeyIsReportedAndIgnored(t *testing.T) {
+	payload := testPayload()
+	payload.Config.SettingsKeys = settingsKeys{Save: []string{"tab"}}
+	m := newModel(payload)
+	if m.err == nil || !strings.Contains(m.err.Error(), "save tab (used for next section)") {
+		t.Fatalf("expected the clash to be reported, got %v", m.err)
+	}
+	next := press(m, tea.KeyMsg{Type: tea.KeyTab})
+	if next.quitting || next.section == m.section {
+		t.Fatalf("expected tab to switch section, quitting=%v", next.quitting)
+	}
+}
+
+func TestConfiguredSaveKeySavesAndQuits(t *testing.T) {
+	payload := testPayload()
+	payload.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	payload.Config.SettingsKeys = settingsKeys{Save: []string{"ctrl+w"}}
+	m := newModel(payload)
+	m.writePolicy = configWritePolicy{dir: filepath.Dir(payload.ConfigPath)}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if updated.(model).quitting {
+		t.Fatalf("expected ctrl+s to be unbound")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	next := updated.(model)
+	if next.err != nil || !next.quitting || cmd == nil {
+		t.Fatalf("expected ctrl+w to save and quit, err=%v quitting=%v", next.err, next.quitting)
+	}
+}
+
+func TestPrintableBackKeyTypesIntoTextInput(t *testing.T) {
+	m := newModel(testPayload())
+	m.messageInput.SetValue("")
+	m.messageInput.Focus()
+	m.screen = screenMessageEditor
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	next := updated.(model)
+
+	if next.screen != screenMessageEditor {
+		t.Fatalf("expected to stay in message editor, got %v", next.screen)
+	}
+	if next.messageInput.Value() != "q" {
+		t.Fatalf("expected q to be typed, got %q", next.messageInput.Value())
+	}
+}
+
+func TestPrintableBackKeyTypesIntoListFilter(t *testing.T) {
+	for _, screen := range []screen{screenFontPicker, screenKeyPicker, screenKeymapPresetPicker} {
+		m := newModel(testPayload())
+		m.screen = screen
+		m = press(m, runeKey("/"))
+		m = press(m, runeKey("q"))
+
+		l, _ := m.activeList()
+		if m.screen != screen || !l.SettingFilter() || l.FilterValue() != "q" {
+			t.Fatalf("screen %v: expected q in the filter, got screen %v filter %q", screen, m.screen, l.FilterValue())
+		}
+		m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.screen != screenMain {
+			t.Fatalf("screen %v: expected esc to go back, got %v", screen, m.screen)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type section int
+
+const (
+	// sectionActions marks entries (save, cancel) listed under every section.
+	sectionActions section = iota - 1
+	sectionDisplay
+	sectionAlerts
+	sectionKeybindings
+	sectionPomodoro
+	sectionAdvanced
+)
+
+var sectionNames = []string{"Display", "Alerts", "Keybindings", "Pomodoro", "Advanced"}
+
+const (
+	twoPaneMinWidth = 100
+	minMenuWidth    = 36
+)
+
+var (
+	activeTabStyle   = lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1)
+	inactiveTabStyle = lipgloss.NewStyle().Faint(true).Padding(0, 1)
+	detailPaneStyle  = lipgloss.NewStyle().
+				Border(lipgloss.NormalBorder(), false, false, false, true).
+				PaddingLeft(2)
+	detailTitleStyle = lipgloss.NewStyle().Bold(true)
+)
+
+func sectionMenuItems(items []list.Item, s section) []list.Item {
+	result := make([]list.Item, 0, len(items))
+	for _, item := range items {
+		entry, ok := item.(menuEntry)
+		if ok && (entry.section == s || entry.section == sectionActions) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (m *model) setSection(s section) {
+	count := section(len(sectionNames))
+	m.section = ((s % count) + count) % count
+	m.refreshMenu()
+	m.menu.Select(0)
+}
+
+func (m model) twoPane() bool {
+	return m.width >= twoPaneMinWidth
+}
+
+func (m model) menuPaneWidth() int {
+	if !m.twoPane() {
+		return m.width
+	}
+	width := m.width * 45 / 100
+	if width < minMenuWidth {
+		width = minMenuWidth
+	}
+	return width
+}
+
+// resize lays out every screen for a terminal of the given size. The main
+// screen loses three lines to the tab bar and the footer.
+func (m *model) resize(width, height int) {
+	m.width = width
+	m.height = height
+	m.menu.SetSize(m.menuPaneWidth(), height-6)
+	m.fontList.SetSize(width, height-4)
+	m.keyList.SetSize(width, height-4)
+	m.keymapList.SetSize(width, height-4)
+	m.timerPresetList.SetSize(width, height-4)
+	m.paletteList.SetSize(width, height-6)
+	m.help.Width = width
+	if width > 26 {
+		m.tickInput.Width = width - 26
+		m.messageInput.Width = width - 26
+		m.tzInput.Width = width - 26
+		m.numberInput.Width = width - 26
+		for idx := range m.timerPresetForm.inputs {
+			m.timerPresetForm.inputs[idx].Width = width - 26
+		}
+		m.keymapInput.Width = width - 26
+		m.paletteInput.Width = width - 4
+	}
+}
+
+func (m model) tabsView() string {
+	tabs := make([]string, 0, len(sectionNames))
+	for idx, name := range sectionNames {
+		if section(idx) == m.section {
+			tabs = append(tabs, activeTabStyle.Render(name))
+		} else {
+			tabs = append(tabs, inactiveTabStyle.Render(name))
+		}
+	}
+	return strings.Join(tabs, " ")
+}
+
+func (m model) detailPaneView(width int) string {
+	selected, ok := m.menu.SelectedItem().(menuEntry)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(detailTitleStyle.Render(selected.title))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Current: %s\n\n", selected.description)
+	if selected.locked {
+		b.WriteString(wrapText("Locked by your administrator's policy file; it cannot be changed here.", width-3) + "\n\n")
+	}
+	b.WriteString(wrapText(settingDetail(selected.id), width-3))
+	return detailPaneStyle.Width(width).Render(b.String())
+}
+
+func (m model) mainView(errorLine string, footer string) string {
+	body := m.menu.View()
+	if m.twoPane() {
+		detailWidth := m.width - m.menuPaneWidth() - 2
+		body = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			lipgloss.NewStyle().Width(m.menuPaneWidth()).Render(body),
+			" ",
+			m.detailPaneView(detailWidth),
+		)
+	}
+	return m.tabsView() + "\n\n" + body + errorLine + "\n" + footer
+}
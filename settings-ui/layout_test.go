@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTabSwitchesSections(t *testing.T) {
+	m := newModel(testPayload())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	next := updated.(model)
+	if next.section != sectionAlerts {
+		t.Fatalf("expected tab to open Alerts, got %v", next.section)
+	}
+	first, ok := next.menu.SelectedItem().(menuEntry)
+	if !ok || first.id != "message" {
+		t.Fatalf("expected first Alerts entry to be message, got %+v", first)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if updated.(model).section != sectionAdvanced {
+		t.Fatalf("expected shift+tab to wrap to Advanced, got %v", updated.(model).section)
+	}
+}
+
+func TestEverySectionEndsWithActions(t *testing.T) {
+	all := buildMenuItems(testPayload().Config, builtinKeymapPresets)
+	for idx := range sectionNames {
+		items := sectionMenuItems(all, section(idx))
+		last := items[len(items)-1].(menuEntry)
+		if last.id != "cancel" {
+			t.Fatalf("section %s does not end with cancel: %+v", sectionNames[idx], last)
+		}
+	}
+}
+
+func TestWideTerminalShowsDetailPane(t *testing.T) {
+	m := newModel(testPayload())
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 30})
+	wide := updated.(model).View()
+	if !strings.Contains(wide, "Current: Standard") {
+		t.Fatalf("expected detail pane on wide terminal, got:\n%s", wide)
+	}
+
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 60, Height: 30})
+	narrow := updated.(model).View()
+	if strings.Contains(narrow, "Current: Standard") {
+		t.Fatalf("expected single pane on narrow terminal, got:\n%s", narrow)
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,6 +33,8 @@ type keybindings struct {
 	StyleKey    string `json:"styleKey"`
 	ExitKey     string `json:"exitKey"`
 	ExitAltKey  string `json:"exitAltKey"`
+	LapKey      string `json:"lapKey"`
+	DeleteKey   string `json:"deleteKey"`
 }
 
 var defaultKeybindings = keybindings{
@@ -38,42 +44,85 @@ var defaultKeybindings = keybindings{
 	StyleKey:    "f",
 	ExitKey:     "q",
 	ExitAltKey:  "e",
+	LapKey:      "l",
+	DeleteKey:   "d",
 }
 
 type config struct {
-	Font                string      `json:"font"`
-	CenterDisplay       bool        `json:"centerDisplay"`
-	ShowHeader          bool        `json:"showHeader"`
-	ShowControls        bool        `json:"showControls"`
-	TickRateMs          int         `json:"tickRateMs"`
-	CompletionMessage   string      `json:"completionMessage"`
-	NotifyOnComplete    bool        `json:"notifyOnComplete"`
-	PlaySoundOnComplete bool        `json:"playSoundOnComplete"`
-	Keybindings         keybindings `json:"keybindings"`
+	Font                string         `json:"font"`
+	CenterDisplay       bool           `json:"centerDisplay"`
+	ShowHeader          bool           `json:"showHeader"`
+	ShowControls        bool           `json:"showControls"`
+	TickRateMs          int            `json:"tickRateMs"`
+	CompletionMessage   string         `json:"completionMessage"`
+	NotifyOnComplete    bool           `json:"notifyOnComplete"`
+	PlaySoundOnComplete bool           `json:"playSoundOnComplete"`
+	Keybindings         keybindings    `json:"keybindings"`
+	SettingsKeys        settingsKeys   `json:"settingsKeys"`
+	FavoriteFonts       []string       `json:"favoriteFonts"`
+	RandomFromFavorites bool           `json:"randomFromFavorites"`
+	TimeZone            string         `json:"timeZone"`
+	DayFormat           string         `json:"dayFormat"`
+	Pomodoro            pomodoroConfig `json:"pomodoro"`
+	PlanDir             string         `json:"planDir"`
+	Presets             []timerPreset  `json:"presets"`
+	// HistoryRetentionDays bounds how far back history.jsonl goes.
+	HistoryRetentionDays int `json:"historyRetentionDays"`
 }
 
 type statePayload struct {
 	ConfigPath string   `json:"configPath"`
 	Config     config   `json:"config"`
 	Fonts      []string `json:"fonts"`
+	FontDir    string   `json:"fontDir"`
 }
 
 type menuEntry struct {
 	id          string
 	title       string
 	description string
+	section     section
+	modified    bool
+	locked      bool
 }
 
-func (m menuEntry) Title() string       { return m.title }
+func (m menuEntry) Title() string {
+	if m.locked {
+		return m.title + " [locked]"
+	}
+	return m.title
+}
 func (m menuEntry) Description() string { return m.description }
 func (m menuEntry) FilterValue() string { return m.title + " " + m.description }
 
 type fontEntry struct {
-	name string
+	name     string
+	favorite bool
+	recent   bool
+	// metrics is set when the picker sorts by rendered size.
+	metrics *fontMetrics
 }
 
-func (f fontEntry) Title() string       { return f.name }
-func (f fontEntry) Description() string { return "Press Enter to select" }
+func (f fontEntry) Title() string {
+	if f.favorite {
+		return "★ " + f.name
+	}
+	return f.name
+}
+
+func (f fontEntry) Description() string {
+	var parts []string
+	if f.favorite {
+		parts = append(parts, "Favorite")
+	}
+	if f.recent {
+		parts = append(parts, "Recently used")
+	}
+	if f.metrics != nil {
+		parts = append(parts, fmt.Sprintf("%d lines x %d columns", f.metrics.height, f.metrics.width))
+	}
+	return strings.Join(append(parts, "Press Enter to select"), " | ")
+}
 func (f fontEntry) FilterValue() string { return f.name }
 
 type keyEntry struct {
@@ -92,20 +141,77 @@ const (
 	screenKeyPicker
 	screenTickRateEditor
 	screenMessageEditor
+	screenKeymapPresetPicker
+	screenKeymapExport
+	screenPalette
+	screenPreview
+	screenConfirmReset
+	screenRestoreDraft
+	screenTimeZoneEditor
+	screenNumberEditor
+	screenTimerPresetList
+	screenTimerPresetForm
+	screenStats
 )
 
 type model struct {
-	payload      statePayload
-	menu         list.Model
-	fontList     list.Model
-	keyList      list.Model
-	tickInput    textinput.Model
-	messageInput textinput.Model
-	screen       screen
-	keyTarget    string
-	quitting     bool
-	cancelled    bool
-	err          error
+	payload     statePayload
+	writePolicy configWritePolicy
+	admin       adminPolicy
+	menu        list.Model
+	fontList    list.Model
+	keyList     list.Model
+	keymapList  list.Model
+	// timerPresetList and timerPresetForm edit the "presets" config list;
+	// presets on its own holds the keymap presets.
+	timerPresetList list.Model
+	timerPresetForm timerPresetForm
+	tickInput       textinput.Model
+	messageInput    textinput.Model
+	tzInput         textinput.Model
+	numberInput     textinput.Model
+	keymapInput     textinput.Model
+	paletteInput    textinput.Model
+	paletteList     list.Model
+	fonts           *fontLibrary
+	presets         []keymapPreset
+	keys            settingsKeyMap
+	help            help.Model
+	showHelp        bool
+	screen          screen
+	section         section
+	previewState    frameState
+	// history is the session log shown on screenStats, read when it opens.
+	history     []historyEntry
+	statsPeriod statsPeriod
+	// tickSampleID and tickSampleFrames drive the live sample in the tick
+	// rate editor; see tickSampleMsg.
+	tickSampleID     int
+	tickSampleFrames int
+	width            int
+	height           int
+	keyTarget        string
+	// numberTarget and messageTarget are the numberFields and messageFields
+	// keys of the setting open in those editors.
+	numberTarget  string
+	messageTarget string
+	// recentFonts is most recent first; pendingFontSelection is the font
+	// picker cursor from the last session, used the first time it opens.
+	recentFonts          []string
+	pendingFontSelection string
+	fontSort             fontSortMode
+	// paletteCommands is rebuilt each time the palette opens, so values
+	// reflect the current config.
+	paletteCommands []paletteCommand
+	notice          string
+	// draft holds changes autosaved by an earlier session while the restore
+	// prompt is open.
+	draft     *config
+	draftTime time.Time
+	crash     *crashReport
+	quitting  bool
+	cancelled bool
+	err       error
 }
 
 func boolText(v bool) string {
@@ -145,35 +251,65 @@ func supportedKeyTokens() []string {
 	return tokens
 }
 
-func buildMenuItems(cfg config) []list.Item {
-	return []list.Item{
-		menuEntry{id: "font", title: "Font", description: cfg.Font},
-		menuEntry{id: "center", title: "Center display", description: boolText(cfg.CenterDisplay)},
-		menuEntry{id: "header", title: "Show header", description: boolText(cfg.ShowHeader)},
-		menuEntry{id: "controls", title: "Show controls", description: boolText(cfg.ShowControls)},
-		menuEntry{id: "tickRate", title: "Tick rate", description: fmt.Sprintf("%d ms", cfg.TickRateMs)},
-		menuEntry{id: "message", title: "Completion message", description: summarizeMessage(cfg.CompletionMessage)},
-		menuEntry{id: "notify", title: "System notification", description: boolText(cfg.NotifyOnComplete)},
-		menuEntry{id: "sound", title: "Completion sound/alarm", description: boolText(cfg.PlaySoundOnComplete)},
-		menuEntry{id: "pauseKey", title: "Pause key", description: keyTokenLabel(cfg.Keybindings.PauseKey)},
-		menuEntry{id: "pauseAltKey", title: "Pause alt key", description: keyTokenLabel(cfg.Keybindings.PauseAltKey)},
-		menuEntry{id: "restartKey", title: "Restart key", description: keyTokenLabel(cfg.Keybindings.RestartKey)},
-		menuEntry{id: "styleKey", title: "Style key", description: keyTokenLabel(cfg.Keybindings.StyleKey)},
-		menuEntry{id: "exitKey", title: "Exit key", description: keyTokenLabel(cfg.Keybindings.ExitKey)},
-		menuEntry{id: "exitAltKey", title: "Exit alt key", description: keyTokenLabel(cfg.Keybindings.ExitAltKey)},
-		menuEntry{id: "save", title: "Save and exit", description: "Write settings and close"},
-		menuEntry{id: "cancel", title: "Cancel", description: "Discard changes"},
-	}
-}
-
-func buildFontItems(fonts []string) []list.Item {
-	items := make([]list.Item, 0, len(fonts))
-	for _, font := range fonts {
-		items = append(items, fontEntry{name: font})
+// buildMenuItems lists every menu entry; values that differ from the
+// defaults get the default appended to their description.
+func buildMenuItems(cfg config, presets []keymapPreset) []list.Item {
+	entries := baseMenuEntries(cfg, presets)
+	defaults := baseMenuEntries(defaultConfig(), presets)
+	items := make([]list.Item, 0, len(entries))
+	for idx, entry := range entries {
+		if entry.section != sectionActions && entry.description != defaults[idx].description {
+			entry.modified = true
+			entry.description = fmt.Sprintf("%s (default: %s)", entry.description, defaults[idx].description)
+		}
+		items = append(items, entry)
 	}
 	return items
 }
 
+func baseMenuEntries(cfg config, presets []keymapPreset) []menuEntry {
+	return []menuEntry{
+		{id: "font", title: "Font", description: cfg.Font, section: sectionDisplay},
+		{id: "randomFavorites", title: "Random style from favorites", description: boolText(cfg.RandomFromFavorites), section: sectionDisplay},
+		{id: "center", title: "Center display", description: boolText(cfg.CenterDisplay), section: sectionDisplay},
+		{id: "header", title: "Show header", description: boolText(cfg.ShowHeader), section: sectionDisplay},
+		{id: "controls", title: "Show controls", description: boolText(cfg.ShowControls), section: sectionDisplay},
+		{id: "dayFormat", title: "Day format", description: dayFormatLabel(cfg.DayFormat), section: sectionDisplay},
+		{id: "preview", title: "Preview timer", description: "See the timer frame as configured", section: sectionDisplay},
+		{id: "message", title: "Completion message", description: summarizeMessage(cfg.CompletionMessage), section: sectionAlerts},
+		{id: "notify", title: "System notification", description: boolText(cfg.NotifyOnComplete), section: sectionAlerts},
+		{id: "sound", title: "Completion sound/alarm", description: boolText(cfg.PlaySoundOnComplete), section: sectionAlerts},
+		{id: "keymapPreset", title: "Keymap preset", description: matchKeymapPreset(presets, cfg.Keybindings), section: sectionKeybindings},
+		{id: "pauseKey", title: "Pause key", description: keyTokenLabel(cfg.Keybindings.PauseKey), section: sectionKeybindings},
+		{id: "pauseAltKey", title: "Pause alt key", description: keyTokenLabel(cfg.Keybindings.PauseAltKey), section: sectionKeybindings},
+		{id: "restartKey", title: "Restart key", description: keyTokenLabel(cfg.Keybindings.RestartKey), section: sectionKeybindings},
+		{id: "styleKey", title: "Style key", description: keyTokenLabel(cfg.Keybindings.StyleKey), section: sectionKeybindings},
+		{id: "exitKey", title: "Exit key", description: keyTokenLabel(cfg.Keybindings.ExitKey), section: sectionKeybindings},
+		{id: "exitAltKey", title: "Exit alt key", description: keyTokenLabel(cfg.Keybindings.ExitAltKey), section: sectionKeybindings},
+		{id: "lapKey", title: "Lap key", description: keyTokenLabel(cfg.Keybindings.LapKey), section: sectionKeybindings},
+		{id: "deleteKey", title: "Delete key", description: keyTokenLabel(cfg.Keybindings.DeleteKey), section: sectionKeybindings},
+		{id: "exportKeymap", title: "Export keymap preset", description: "Save current keys as a named preset file", section: sectionKeybindings},
+		{id: "pomodoroFocus", title: "Focus length", description: numberText(cfg.Pomodoro.FocusMinutes, "min"), section: sectionPomodoro},
+		{id: "pomodoroShortBreak", title: "Short break", description: numberText(cfg.Pomodoro.ShortBreakMinutes, "min"), section: sectionPomodoro},
+		{id: "pomodoroLongBreak", title: "Long break", description: numberText(cfg.Pomodoro.LongBreakMinutes, "min"), section: sectionPomodoro},
+		{id: "pomodoroCycles", title: "Cycles before long break", description: numberText(cfg.Pomodoro.CyclesBeforeLongBreak, ""), section: sectionPomodoro},
+		{id: "pomodoroAutoBreaks", title: "Auto-start breaks", description: boolText(cfg.Pomodoro.AutoStartBreaks), section: sectionPomodoro},
+		{id: "pomodoroAutoFocus", title: "Auto-start focus", description: boolText(cfg.Pomodoro.AutoStartFocus), section: sectionPomodoro},
+		{id: "pomodoroFocusMessage", title: "Message after focus", description: summarizeMessage(cfg.Pomodoro.FocusMessage), section: sectionPomodoro},
+		{id: "pomodoroShortMessage", title: "Message after short break", description: summarizeMessage(cfg.Pomodoro.ShortBreakMessage), section: sectionPomodoro},
+		{id: "pomodoroLongMessage", title: "Message after long break", description: summarizeMessage(cfg.Pomodoro.LongBreakMessage), section: sectionPomodoro},
+		{id: "presets", title: "Timer presets", description: timerPresetsSummary(cfg.Presets), section: sectionAdvanced},
+		{id: "tickRate", title: "Tick rate", description: fmt.Sprintf("%d ms", cfg.TickRateMs), section: sectionAdvanced},
+		{id: "timeZone", title: "Default time zone", description: timeZoneLabel(cfg.TimeZone), section: sectionAdvanced},
+		{id: "planDir", title: "Plan directory", description: planDirLabel(cfg.PlanDir), section: sectionAdvanced},
+		{id: "stats", title: "Session statistics", description: "Totals, streaks and a heatmap from the history", section: sectionAdvanced},
+		{id: "historyRetention", title: "History retention", description: numberText(cfg.HistoryRetentionDays, "days"), section: sectionAdvanced},
+		{id: "resetAll", title: "Reset all to defaults", description: "Restore every setting on these tabs", section: sectionAdvanced},
+		{id: "save", title: "Save and exit", description: "Write settings and close", section: sectionActions},
+		{id: "cancel", title: "Cancel", description: "Discard changes", section: sectionActions},
+	}
+}
+
 func buildKeyItems() []list.Item {
 	items := make([]list.Item, 0, len(supportedKeyTokens()))
 	for _, token := range supportedKeyTokens() {
@@ -227,21 +363,33 @@ func normalizeKeybindings(cfg keybindings) keybindings {
 	result.StyleKey = normalizeKeyToken(cfg.StyleKey, result.StyleKey)
 	result.ExitKey = normalizeKeyToken(cfg.ExitKey, result.ExitKey)
 	result.ExitAltKey = normalizeKeyToken(cfg.ExitAltKey, result.ExitAltKey)
+	result.LapKey = normalizeKeyToken(cfg.LapKey, result.LapKey)
+	result.DeleteKey = normalizeKeyToken(cfg.DeleteKey, result.DeleteKey)
 	return result
 }
 
-func normalizeConfig(cfg config) config {
-	result := config{
-		Font:                defaultFont,
-		CenterDisplay:       true,
-		ShowHeader:          true,
-		ShowControls:        true,
-		TickRateMs:          defaultTickRateMs,
-		CompletionMessage:   defaultCompletionMessage,
-		NotifyOnComplete:    true,
-		PlaySoundOnComplete: false,
-		Keybindings:         defaultKeybindings,
+func defaultConfig() config {
+	return config{
+		Font:                 defaultFont,
+		CenterDisplay:        true,
+		ShowHeader:           true,
+		ShowControls:         true,
+		TickRateMs:           defaultTickRateMs,
+		CompletionMessage:    defaultCompletionMessage,
+		NotifyOnComplete:     true,
+		PlaySoundOnComplete:  false,
+		Keybindings:          defaultKeybindings,
+		SettingsKeys:         normalizeSettingsKeys(settingsKeys{}),
+		FavoriteFonts:        []string{},
+		DayFormat:            dayFormatDays,
+		Pomodoro:             defaultPomodoro,
+		Presets:              []timerPreset{},
+		HistoryRetentionDays: defaultHistoryRetentionDays,
 	}
+}
+
+func normalizeConfig(cfg config) config {
+	result := defaultConfig()
 
 	if strings.TrimSpace(cfg.Font) != "" {
 		result.Font = cfg.Font
@@ -258,6 +406,15 @@ func normalizeConfig(cfg config) config {
 	result.NotifyOnComplete = cfg.NotifyOnComplete
 	result.PlaySoundOnComplete = cfg.PlaySoundOnComplete
 	result.Keybindings = normalizeKeybindings(cfg.Keybindings)
+	result.SettingsKeys = normalizeSettingsKeys(cfg.SettingsKeys)
+	result.FavoriteFonts = normalizeFavoriteFonts(cfg.FavoriteFonts)
+	result.RandomFromFavorites = cfg.RandomFromFavorites
+	result.TimeZone = normalizeTimeZone(cfg.TimeZone)
+	result.DayFormat = normalizeDayFormat(cfg.DayFormat)
+	result.Pomodoro = normalizePomodoro(cfg.Pomodoro)
+	result.PlanDir = strings.TrimSpace(cfg.PlanDir)
+	result.Presets = normalizeTimerPresets(cfg.Presets)
+	result.HistoryRetentionDays = sanitizeHistoryRetention(cfg.HistoryRetentionDays)
 	return result
 }
 
@@ -274,23 +431,12 @@ func isConfirmKey(msg tea.KeyMsg) bool {
 	return s == "enter" || s == "ctrl+m" || s == "ctrl+j" || s == "return"
 }
 
-func isBackKey(msg tea.KeyMsg) bool {
-	s := msg.String()
-	return s == "esc" || s == "q"
-}
-
-func isQuitKey(msg tea.KeyMsg) bool {
-	s := msg.String()
-	return s == "ctrl+c" || s == "q"
-}
-
-func isSaveKey(msg tea.KeyMsg) bool {
-	s := msg.String()
-	return s == "ctrl+s"
-}
-
 func newModel(payload statePayload) model {
-	menuModel := list.New(buildMenuItems(payload.Config), list.NewDefaultDelegate(), 0, 0)
+	customPresets, presetErr := loadKeymapPresetFiles(keymapPresetDir(payload.ConfigPath))
+	presets := mergeKeymapPresets(builtinKeymapPresets, customPresets)
+
+	menuItems := sectionMenuItems(buildMenuItems(payload.Config, presets), sectionDisplay)
+	menuModel := list.New(menuItems, list.NewDefaultDelegate(), 0, 0)
 	menuModel.Title = "Timer Settings"
 	menuModel.SetShowHelp(true)
 	menuModel.SetFilteringEnabled(false)
@@ -298,8 +444,14 @@ func newModel(payload statePayload) model {
 	menuModel.SetShowStatusBar(false)
 	menuModel.SetSize(100, 20)
 
-	fontModel := list.New(buildFontItems(payload.Fonts), list.NewDefaultDelegate(), 0, 0)
-	fontModel.Title = "Select Font"
+	var state uiState
+	if payload.ConfigPath != "" {
+		state = loadUIState(uiStatePath(payload.ConfigPath))
+	}
+
+	fontSort := parseFontSortMode(state.FontSort)
+	fontModel := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	fontModel.Title = fontPickerTitle(fontSort)
 	fontModel.SetShowHelp(true)
 	fontModel.SetFilteringEnabled(true)
 	fontModel.DisableQuitKeybindings()
@@ -312,6 +464,20 @@ func newModel(payload statePayload) model {
 	keyModel.DisableQuitKeybindings()
 	keyModel.SetSize(100, 20)
 
+	keymapModel := list.New(buildKeymapPresetItems(presets), list.NewDefaultDelegate(), 0, 0)
+	keymapModel.Title = "Select Keymap Preset"
+	keymapModel.SetShowHelp(true)
+	keymapModel.SetFilteringEnabled(true)
+	keymapModel.DisableQuitKeybindings()
+	keymapModel.SetSize(100, 20)
+
+	timerPresetModel := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	timerPresetModel.Title = "Timer presets"
+	timerPresetModel.SetShowHelp(false)
+	timerPresetModel.SetFilteringEnabled(false)
+	timerPresetModel.DisableQuitKeybindings()
+	timerPresetModel.SetSize(100, 20)
+
 	tickInput := textinput.New()
 	tickInput.Prompt = "Tick rate (ms): "
 	tickInput.CharLimit = 4
@@ -324,15 +490,60 @@ func newModel(payload statePayload) model {
 	messageInput.SetValue(payload.Config.CompletionMessage)
 	messageInput.Blur()
 
-	return model{
-		payload:      payload,
-		menu:         menuModel,
-		fontList:     fontModel,
-		keyList:      keyModel,
-		tickInput:    tickInput,
-		messageInput: messageInput,
-		screen:       screenMain,
+	keymapInput := textinput.New()
+	keymapInput.Prompt = "Preset name: "
+	keymapInput.CharLimit = 64
+	keymapInput.Blur()
+
+	paletteInput := textinput.New()
+	paletteInput.Prompt = "> "
+	paletteInput.Placeholder = "Search settings, actions and values"
+	paletteInput.Blur()
+
+	paletteModel := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	paletteModel.SetShowTitle(false)
+	paletteModel.SetShowHelp(false)
+	paletteModel.SetShowStatusBar(false)
+	paletteModel.SetFilteringEnabled(false)
+	paletteModel.DisableQuitKeybindings()
+	paletteModel.SetSize(100, 16)
+
+	helpModel := help.New()
+	helpModel.Width = 100
+
+	m := model{
+		payload:         payload,
+		writePolicy:     defaultConfigWritePolicy(),
+		menu:            menuModel,
+		fontList:        fontModel,
+		keyList:         keyModel,
+		keymapList:      keymapModel,
+		timerPresetList: timerPresetModel,
+		tickInput:       tickInput,
+		messageInput:    messageInput,
+		tzInput:         newTimeZoneInput(payload.Config.TimeZone),
+		numberInput:     newNumberInput(),
+		messageTarget:   "message",
+		keymapInput:     keymapInput,
+		paletteInput:    paletteInput,
+		paletteList:     paletteModel,
+		fonts:           newFontLibrary(payload.FontDir),
+		crash:           &crashReport{},
+		presets:         presets,
+		keys:            newSettingsKeyMap(payload.Config.SettingsKeys),
+		help:            helpModel,
+		screen:          screenMain,
+		section:         sectionDisplay,
+		recentFonts:     state.RecentFonts,
+		fontSort:        fontSort,
+		err:             presetErr,
+	}
+	if _, dropped := resolveSettingsKeys(payload.Config.SettingsKeys); len(dropped) > 0 && m.err == nil {
+		m.err = fmt.Errorf("ignored settings keys: %s", strings.Join(dropped, ", "))
 	}
+	m.fontList.SetItems(m.fontItems())
+	m.restoreUIState(state)
+	return m
 }
 
 func (m model) Init() tea.Cmd {
@@ -340,23 +551,16 @@ func (m model) Init() tea.Cmd {
 }
 
 func (m *model) refreshMenu() {
-	m.menu.SetItems(buildMenuItems(m.payload.Config))
+	m.menu.SetItems(sectionMenuItems(m.admin.markLocked(buildMenuItems(m.payload.Config, m.presets)), m.section))
 }
 
 func (m *model) save() error {
-	if m.payload.ConfigPath == "" {
-		return errors.New("config path is missing")
-	}
-	text, err := json.MarshalIndent(m.payload.Config, "", "  ")
-	if err != nil {
-		return err
-	}
-	text = append(text, '\n')
-	return os.WriteFile(m.payload.ConfigPath, text, 0644)
+	m.payload.Config = m.admin.apply(m.payload.Config)
+	return m.writePolicy.writeConfigFile(m.payload.ConfigPath, m.payload.Config)
 }
 
 func (m *model) selectFontItem(font string) {
-	for idx, item := range m.fontList.Items() {
+	for idx, item := range m.fontList.VisibleItems() {
 		entry, ok := item.(fontEntry)
 		if ok && entry.name == font {
 			m.fontList.Select(idx)
@@ -375,26 +579,65 @@ func (m *model) selectKeyItem(token string) {
 	}
 }
 
-func (m *model) keyTokenForTarget(target string) string {
+func (m *model) selectKeymapPresetItem(name string) {
+	for idx, item := range m.keymapList.Items() {
+		entry, ok := item.(keymapPresetEntry)
+		if ok && entry.preset.Name == name {
+			m.keymapList.Select(idx)
+			return
+		}
+	}
+}
+
+func keyTokenForTarget(kb keybindings, target string) string {
 	switch target {
 	case "pauseKey":
-		return m.payload.Config.Keybindings.PauseKey
+		return kb.PauseKey
 	case "pauseAltKey":
-		return m.payload.Config.Keybindings.PauseAltKey
+		return kb.PauseAltKey
 	case "restartKey":
-		return m.payload.Config.Keybindings.RestartKey
+		return kb.RestartKey
 	case "styleKey":
-		return m.payload.Config.Keybindings.StyleKey
+		return kb.StyleKey
 	case "exitKey":
-		return m.payload.Config.Keybindings.ExitKey
+		return kb.ExitKey
 	case "exitAltKey":
-		return m.payload.Config.Keybindings.ExitAltKey
+		return kb.ExitAltKey
+	case "lapKey":
+		return kb.LapKey
+	case "deleteKey":
+		return kb.DeleteKey
 	default:
 		return defaultKeybindings.PauseKey
 	}
 }
 
-func (m *model) setKeyTokenForTarget(target string, token string) {
+func (m *model) keyTokenForTarget(target string) string {
+	return keyTokenForTarget(m.payload.Config.Keybindings, target)
+}
+
+// keyTargets are the timer keys the settings UI edits, with their menu
+// titles. They are all active at once, so no two may share a key.
+var keyTargets = []struct{ id, title string }{
+	{"pauseKey", "Pause key"},
+	{"pauseAltKey", "Pause alt key"},
+	{"restartKey", "Restart key"},
+	{"styleKey", "Style key"},
+	{"exitKey", "Exit key"},
+	{"exitAltKey", "Exit alt key"},
+	{"lapKey", "Lap key"},
+	{"deleteKey", "Delete key"},
+}
+
+// setKeyTokenForTarget binds token to target. A key already bound to another
+// action is refused: whichever the clock checks first would silently win,
+// leaving pause or exit unreachable.
+func (m *model) setKeyTokenForTarget(target string, token string) error {
+	for _, other := range keyTargets {
+		if other.id != target && m.keyTokenForTarget(other.id) == token {
+			return fmt.Errorf("%s is already the %s", keyTokenLabel(token), strings.ToLower(other.title))
+		}
+	}
 	switch target {
 	case "pauseKey":
 		m.payload.Config.Keybindings.PauseKey = token
@@ -408,7 +651,12 @@ func (m *model) setKeyTokenForTarget(target string, token string) {
 		m.payload.Config.Keybindings.ExitKey = token
 	case "exitAltKey":
 		m.payload.Config.Keybindings.ExitAltKey = token
+	case "lapKey":
+		m.payload.Config.Keybindings.LapKey = token
+	case "deleteKey":
+		m.payload.Config.Keybindings.DeleteKey = token
 	}
+	return nil
 }
 
 func (m *model) saveAndQuit() tea.Cmd {
@@ -416,6 +664,20 @@ func (m *model) saveAndQuit() tea.Cmd {
 		m.err = err
 		return nil
 	}
+	m.err = nil
+	m.removeDraft()
+	m.saveUIState()
+	m.quitting = true
+	return tea.Quit
+}
+
+// cancelAndQuit leaves without saving. Errors shown during the session (a
+// bad preset file, say) are dropped so they are not reported as save errors.
+func (m *model) cancelAndQuit() tea.Cmd {
+	m.err = nil
+	m.removeDraft()
+	m.saveUIState()
+	m.cancelled = true
 	m.quitting = true
 	return tea.Quit
 }
@@ -440,11 +702,19 @@ func (m *model) applyMenuAction() tea.Cmd {
 		return nil
 	}
 	m.err = nil
+	m.notice = ""
+	if m.admin.menuEntryLocked(selected.id) {
+		m.notice = lockedNotice(selected.title)
+		return nil
+	}
 
 	switch selected.id {
 	case "font":
-		m.selectFontItem(m.payload.Config.Font)
-		m.screen = screenFontPicker
+		m.openFontPicker()
+		return nil
+	case "randomFavorites":
+		m.payload.Config.RandomFromFavorites = !m.payload.Config.RandomFromFavorites
+		m.refreshMenu()
 		return nil
 	case "center":
 		m.payload.Config.CenterDisplay = !m.payload.Config.CenterDisplay
@@ -458,17 +728,42 @@ func (m *model) applyMenuAction() tea.Cmd {
 		m.payload.Config.ShowControls = !m.payload.Config.ShowControls
 		m.refreshMenu()
 		return nil
+	case "dayFormat":
+		if m.payload.Config.DayFormat == dayFormatHours {
+			m.payload.Config.DayFormat = dayFormatDays
+		} else {
+			m.payload.Config.DayFormat = dayFormatHours
+		}
+		m.refreshMenu()
+		return nil
+	case "timeZone":
+		m.openTimeZoneEditor()
+		return nil
+	case "presets":
+		m.openTimerPresetList()
+		return nil
+	case "stats":
+		m.openStats()
+		return nil
+	case "preview":
+		m.previewState = frameRunning
+		m.screen = screenPreview
+		return nil
 	case "tickRate":
-		m.tickInput.SetValue(strconv.Itoa(m.payload.Config.TickRateMs))
-		m.tickInput.CursorEnd()
-		m.tickInput.Focus()
-		m.screen = screenTickRateEditor
+		return m.openTickRateEditor()
+	case "message", "pomodoroFocusMessage", "pomodoroShortMessage", "pomodoroLongMessage", "planDir":
+		m.openMessageEditor(selected.id)
 		return nil
-	case "message":
-		m.messageInput.SetValue(m.payload.Config.CompletionMessage)
-		m.messageInput.CursorEnd()
-		m.messageInput.Focus()
-		m.screen = screenMessageEditor
+	case "pomodoroFocus", "pomodoroShortBreak", "pomodoroLongBreak", "pomodoroCycles", "historyRetention":
+		m.openNumberEditor(selected.id)
+		return nil
+	case "pomodoroAutoBreaks":
+		m.payload.Config.Pomodoro.AutoStartBreaks = !m.payload.Config.Pomodoro.AutoStartBreaks
+		m.refreshMenu()
+		return nil
+	case "pomodoroAutoFocus":
+		m.payload.Config.Pomodoro.AutoStartFocus = !m.payload.Config.Pomodoro.AutoStartFocus
+		m.refreshMenu()
 		return nil
 	case "notify":
 		m.payload.Config.NotifyOnComplete = !m.payload.Config.NotifyOnComplete
@@ -478,6 +773,10 @@ func (m *model) applyMenuAction() tea.Cmd {
 		m.payload.Config.PlaySoundOnComplete = !m.payload.Config.PlaySoundOnComplete
 		m.refreshMenu()
 		return nil
+	case "keymapPreset":
+		m.selectKeymapPresetItem(matchKeymapPreset(m.presets, m.payload.Config.Keybindings))
+		m.screen = screenKeymapPresetPicker
+		return nil
 	case "pauseKey":
 		m.openKeyPicker("pauseKey", "Select Pause Key")
 		return nil
@@ -496,48 +795,102 @@ func (m *model) applyMenuAction() tea.Cmd {
 	case "exitAltKey":
 		m.openKeyPicker("exitAltKey", "Select Exit Alt Key")
 		return nil
+	case "lapKey":
+		m.openKeyPicker("lapKey", "Select Lap Key")
+		return nil
+	case "deleteKey":
+		m.openKeyPicker("deleteKey", "Select Delete Key")
+		return nil
+	case "exportKeymap":
+		m.keymapInput.SetValue("")
+		m.keymapInput.Focus()
+		m.screen = screenKeymapExport
+		return nil
+	case "resetAll":
+		m.screen = screenConfirmReset
+		return nil
 	case "save":
 		return m.saveAndQuit()
 	case "cancel":
-		m.cancelled = true
-		m.quitting = true
-		return tea.Quit
+		return m.cancelAndQuit()
 	default:
 		return nil
 	}
 }
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.menu.SetSize(msg.Width, msg.Height-4)
-		m.fontList.SetSize(msg.Width, msg.Height-4)
-		m.keyList.SetSize(msg.Width, msg.Height-4)
-		if msg.Width > 26 {
-			m.tickInput.Width = msg.Width - 26
-			m.messageInput.Width = msg.Width - 26
-		}
+		m.resize(msg.Width, msg.Height)
 		return m, nil
+	case tickSampleMsg:
+		if msg.id != m.tickSampleID || m.screen != screenTickRateEditor {
+			return m, nil
+		}
+		m.tickSampleFrames++
+		return m, tickSampleCmd(m.tickSampleID, m.editedTickRate())
 	case tea.KeyMsg:
+		if m.showHelp {
+			if key.Matches(msg, m.helpBinding()) || key.Matches(msg, m.keys.Back) {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+		if key.Matches(msg, m.helpBinding()) {
+			m.showHelp = true
+			return m, nil
+		}
+
 		switch m.screen {
 		case screenMain:
-			if isQuitKey(msg) {
-				m.cancelled = true
-				m.quitting = true
-				return m, tea.Quit
+			if key.Matches(msg, m.keys.Quit) {
+				return m, m.cancelAndQuit()
 			}
-			if isSaveKey(msg) {
+			if key.Matches(msg, m.keys.Save) {
 				return m, m.saveAndQuit()
 			}
+			if key.Matches(msg, m.keys.Reset) {
+				selected, ok := m.menu.SelectedItem().(menuEntry)
+				if ok && m.admin.menuEntryLocked(selected.id) {
+					m.notice = lockedNotice(selected.title)
+				} else if ok && m.resetSetting(selected.id) {
+					m.err = nil
+					m.notice = fmt.Sprintf("%s reset to default", selected.title)
+					m.refreshMenu()
+				}
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.Palette) {
+				m.openPalette()
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.NextSection) {
+				m.setSection(m.section + 1)
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.PrevSection) {
+				m.setSection(m.section - 1)
+				return m, nil
+			}
 			if isConfirmKey(msg) {
 				cmd := m.applyMenuAction()
 				return m, cmd
 			}
 		case screenFontPicker:
-			if isBackKey(msg) {
+			if key.Matches(msg, m.backBinding()) {
 				m.screen = screenMain
 				return m, nil
 			}
+			if m.fontList.FilterState() != list.Filtering {
+				if key.Matches(msg, m.keys.Favorite) {
+					m.toggleFavoriteFont()
+					return m, nil
+				}
+				if key.Matches(msg, m.keys.SortFonts) {
+					m.cycleFontSort()
+					return m, nil
+				}
+			}
 			if isConfirmKey(msg) {
 				item, ok := m.fontList.SelectedItem().(fontEntry)
 				if !ok {
@@ -548,14 +901,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				if ok {
-					m.payload.Config.Font = item.name
+					m.chooseFont(item.name)
 					m.screen = screenMain
 					m.refreshMenu()
 				}
 				return m, nil
 			}
 		case screenKeyPicker:
-			if isBackKey(msg) {
+			if key.Matches(msg, m.backBinding()) {
+				m.err = nil
 				m.screen = screenMain
 				return m, nil
 			}
@@ -569,14 +923,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				if ok {
-					m.setKeyTokenForTarget(m.keyTarget, item.token)
+					if err := m.setKeyTokenForTarget(m.keyTarget, item.token); err != nil {
+						m.err = err
+						return m, nil
+					}
+					m.err = nil
 					m.screen = screenMain
 					m.refreshMenu()
 				}
 				return m, nil
 			}
 		case screenTickRateEditor:
-			if isBackKey(msg) {
+			if key.Matches(msg, m.keys.textBack()) {
 				m.tickInput.Blur()
 				m.screen = screenMain
 				return m, nil
@@ -598,20 +956,161 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.refreshMenu()
 				return m, nil
 			}
+			if key.Matches(msg, m.keys.Increase) {
+				return m, m.adjustTickRate(tickRateStepMs)
+			}
+			if key.Matches(msg, m.keys.Decrease) {
+				return m, m.adjustTickRate(-tickRateStepMs)
+			}
+			if key.Matches(msg, m.keys.IncreaseMore) {
+				return m, m.adjustTickRate(tickRateLargeStepMs)
+			}
+			if key.Matches(msg, m.keys.DecreaseMore) {
+				return m, m.adjustTickRate(-tickRateLargeStepMs)
+			}
+			before := m.editedTickRate()
+			var cmd tea.Cmd
+			m.tickInput, cmd = m.tickInput.Update(msg)
+			if m.editedTickRate() != before {
+				return m, tea.Batch(cmd, m.restartTickSample())
+			}
+			return m, cmd
 		case screenMessageEditor:
-			if isBackKey(msg) {
+			if key.Matches(msg, m.keys.textBack()) {
 				m.messageInput.Blur()
 				m.screen = screenMain
 				return m, nil
 			}
 			if isConfirmKey(msg) {
-				m.payload.Config.CompletionMessage = normalizeCompletionMessage(m.messageInput.Value())
+				field := messageFields[m.messageTarget]
+				*field.field(&m.payload.Config) = field.normalize(m.messageInput.Value())
 				m.err = nil
 				m.messageInput.Blur()
 				m.screen = screenMain
 				m.refreshMenu()
 				return m, nil
 			}
+		case screenTimeZoneEditor:
+			if key.Matches(msg, m.keys.textBack()) {
+				m.tzInput.Blur()
+				m.err = nil
+				m.screen = screenMain
+				return m, nil
+			}
+			cmd := m.updateTimeZoneEditor(msg)
+			return m, cmd
+		case screenTimerPresetList:
+			cmd := m.updateTimerPresetList(msg)
+			return m, cmd
+		case screenTimerPresetForm:
+			cmd := m.updateTimerPresetForm(msg)
+			return m, cmd
+		case screenNumberEditor:
+			if key.Matches(msg, m.keys.textBack()) {
+				m.numberInput.Blur()
+				m.err = nil
+				m.screen = screenMain
+				return m, nil
+			}
+			cmd := m.updateNumberEditor(msg)
+			return m, cmd
+		case screenKeymapPresetPicker:
+			if key.Matches(msg, m.backBinding()) {
+				m.screen = screenMain
+				return m, nil
+			}
+			if isConfirmKey(msg) {
+				item, ok := m.keymapList.SelectedItem().(keymapPresetEntry)
+				if ok {
+					m.payload.Config.Keybindings = item.preset.Keybindings
+					m.screen = screenMain
+					m.refreshMenu()
+				}
+				return m, nil
+			}
+		case screenRestoreDraft:
+			if key.Matches(msg, m.keys.Yes) || isConfirmKey(msg) {
+				m.restoreDraft()
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.No) || key.Matches(msg, m.keys.Back) {
+				m.discardDraft()
+				return m, nil
+			}
+			return m, nil
+		case screenConfirmReset:
+			if key.Matches(msg, m.keys.Yes) || isConfirmKey(msg) {
+				m.resetAll()
+				m.notice = "All settings reset to defaults (not saved yet)"
+				m.screen = screenMain
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.No) || key.Matches(msg, m.keys.Back) {
+				m.screen = screenMain
+				return m, nil
+			}
+			return m, nil
+		case screenStats:
+			if key.Matches(msg, m.keys.Back) || isConfirmKey(msg) {
+				m.err = nil
+				m.screen = screenMain
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.NextState) {
+				m.cycleStatsPeriod(1)
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.PrevState) {
+				m.cycleStatsPeriod(-1)
+				return m, nil
+			}
+			return m, nil
+		case screenPreview:
+			if key.Matches(msg, m.keys.Back) || isConfirmKey(msg) {
+				m.screen = screenMain
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.NextState) {
+				m.cyclePreviewState(1)
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.PrevState) {
+				m.cyclePreviewState(-1)
+				return m, nil
+			}
+		case screenPalette:
+			if key.Matches(msg, m.keys.textBack()) {
+				m.closePalette()
+				return m, nil
+			}
+			if isConfirmKey(msg) {
+				cmd := m.runSelectedPaletteCommand()
+				return m, cmd
+			}
+			return m.updatePalette(msg)
+		case screenKeymapExport:
+			if key.Matches(msg, m.keys.textBack()) {
+				m.keymapInput.Blur()
+				m.screen = screenMain
+				return m, nil
+			}
+			if isConfirmKey(msg) {
+				dir := keymapPresetDir(m.payload.ConfigPath)
+				path, err := exportKeymapPreset(dir, m.keymapInput.Value(), m.payload.Config.Keybindings)
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+				custom, err := loadKeymapPresetFiles(dir)
+				m.presets = mergeKeymapPresets(builtinKeymapPresets, custom)
+				m.keymapList.SetItems(buildKeymapPresetItems(m.presets))
+				m.err = err
+				m.notice = fmt.Sprintf("Exported keymap preset to %s", path)
+				m.keymapInput.Blur()
+				m.screen = screenMain
+				m.refreshMenu()
+				return m, nil
+			}
 		}
 	}
 
@@ -627,12 +1126,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tickInput, cmd = m.tickInput.Update(msg)
 	case screenMessageEditor:
 		m.messageInput, cmd = m.messageInput.Update(msg)
+	case screenKeymapPresetPicker:
+		m.keymapList, cmd = m.keymapList.Update(msg)
+	case screenKeymapExport:
+		m.keymapInput, cmd = m.keymapInput.Update(msg)
 	}
 
 	return m, cmd
 }
 
-func (m model) View() string {
+// screenBindings lists the keys handled on the current screen, in footer order.
+func (m model) screenBindings() []key.Binding {
+	switch m.screen {
+	case screenMain:
+		return []key.Binding{m.keys.Confirm, m.keys.Reset, m.keys.Palette, m.keys.NextSection, m.keys.PrevSection, m.keys.Save, m.keys.Quit}
+	case screenConfirmReset:
+		return []key.Binding{m.keys.Yes, m.keys.No}
+	case screenRestoreDraft:
+		return []key.Binding{withHelpDesc(m.keys.Yes, "restore"), withHelpDesc(m.keys.No, "discard")}
+	case screenFontPicker:
+		return []key.Binding{withHelpDesc(m.keys.Confirm, "choose font"), m.keys.Favorite, m.keys.SortFonts, m.keys.Filter, m.backBinding()}
+	case screenKeyPicker:
+		return []key.Binding{withHelpDesc(m.keys.Confirm, "choose key"), m.keys.Filter, m.backBinding()}
+	case screenKeymapPresetPicker:
+		return []key.Binding{withHelpDesc(m.keys.Confirm, "apply preset"), m.keys.Filter, m.backBinding()}
+	case screenTickRateEditor:
+		return []key.Binding{withHelpDesc(m.keys.Confirm, "save"), m.keys.Decrease, m.keys.Increase, m.keys.DecreaseMore, m.keys.IncreaseMore, m.keys.textBack()}
+	case screenMessageEditor, screenTimeZoneEditor:
+		return []key.Binding{withHelpDesc(m.keys.Confirm, "save"), m.keys.textBack()}
+	case screenNumberEditor:
+		return m.numberEditorBindings()
+	case screenTimerPresetList:
+		return m.timerPresetListBindings()
+	case screenTimerPresetForm:
+		return []key.Binding{withHelpDesc(m.keys.Confirm, "save preset"), m.keys.NextField, m.keys.PrevField, m.keys.textBack()}
+	case screenKeymapExport:
+		return []key.Binding{withHelpDesc(m.keys.Confirm, "export"), m.keys.textBack()}
+	case screenPalette:
+		return []key.Binding{withHelpDesc(m.keys.Confirm, "run"), m.keys.Move, m.keys.textBack()}
+	case screenPreview:
+		return []key.Binding{m.keys.NextState, m.keys.PrevState, m.keys.Back}
+	case screenStats:
+		return []key.Binding{withHelpDesc(m.keys.NextState, "next period"), withHelpDesc(m.keys.PrevState, "previous period"), m.keys.Back}
+	default:
+		return nil
+	}
+}
+
+func (m model) view() string {
 	if m.quitting {
 		if m.err != nil {
 			return fmt.Sprintf("Error: %v\n", m.err)
@@ -646,19 +1187,48 @@ func (m model) View() string {
 	errorLine := ""
 	if m.err != nil {
 		errorLine = fmt.Sprintf("\nError: %v\n", m.err)
+	} else if m.notice != "" {
+		errorLine = fmt.Sprintf("\n%s\n", m.notice)
+	}
+
+	if m.showHelp {
+		return m.helpOverlayView()
 	}
 
+	footer := footerLine(append(m.screenBindings(), m.helpBinding())...)
 	switch m.screen {
 	case screenMain:
-		return m.menu.View() + errorLine + "\nEnter: select/edit | Ctrl+S: save and exit | q: cancel | Ctrl+C: cancel"
+		return m.mainView(errorLine, footer)
 	case screenFontPicker:
-		return m.fontList.View() + "\nEnter: choose font | /: filter | esc: back"
+		return m.fontList.View() + errorLine + "\n" + footer
 	case screenKeyPicker:
-		return m.keyList.View() + "\nEnter: choose key | /: filter | esc: back"
+		return m.keyList.View() + errorLine + "\n" + footer
 	case screenTickRateEditor:
-		return fmt.Sprintf("Tick rate (%d-%d ms)\n\n%s%s\n\nEnter: save | esc: back", minTickRateMs, maxTickRateMs, m.tickInput.View(), errorLine)
+		return m.tickRateEditorView(errorLine, footer)
 	case screenMessageEditor:
-		return fmt.Sprintf("Completion message\n\n%s%s\n\nEnter: save | esc: back", m.messageInput.View(), errorLine)
+		return fmt.Sprintf("%s\n\n%s%s\n\n%s", messageFields[m.messageTarget].title, m.messageInput.View(), errorLine, footer)
+	case screenNumberEditor:
+		return m.numberEditorView(errorLine, footer)
+	case screenTimerPresetList:
+		return m.timerPresetListView(errorLine, footer)
+	case screenTimerPresetForm:
+		return m.timerPresetFormView(errorLine, footer)
+	case screenTimeZoneEditor:
+		return m.timeZoneEditorView(errorLine, footer)
+	case screenKeymapPresetPicker:
+		return m.keymapList.View() + "\n" + footer
+	case screenPreview:
+		return m.previewView(footer)
+	case screenStats:
+		return m.statsView(errorLine, footer)
+	case screenRestoreDraft:
+		return m.restoreDraftView(footer)
+	case screenConfirmReset:
+		return "Reset all settings to their defaults?\n\nChanges are only written when you save.\n\n" + footer
+	case screenPalette:
+		return fmt.Sprintf("Command palette\n\n%s\n\n%s\n%s", m.paletteInput.View(), m.paletteList.View(), footer)
+	case screenKeymapExport:
+		return fmt.Sprintf("Export keymap preset to %s\n\n%s%s\n\n%s", keymapPresetDir(m.payload.ConfigPath), m.keymapInput.View(), errorLine, footer)
 	default:
 		return ""
 	}
@@ -675,7 +1245,10 @@ func containsString(values []string, needle string) bool {
 
 func loadPayload(statePath string) (statePayload, error) {
 	if statePath == "" {
-		return statePayload{}, errors.New("--state is required")
+		return statePayload{}, errors.New("--state or --config is required")
+	}
+	if err := checkStateFile(statePath); err != nil {
+		return statePayload{}, err
 	}
 	text, err := os.ReadFile(statePath)
 	if err != nil {
@@ -688,6 +1261,13 @@ func loadPayload(statePath string) (statePayload, error) {
 	if strings.TrimSpace(payload.ConfigPath) == "" {
 		return statePayload{}, errors.New("configPath is missing in state payload")
 	}
+	if payload.FontDir == "" {
+		payload.FontDir = findFontDir(fontDirCandidates())
+	}
+	return finishPayload(payload), nil
+}
+
+func finishPayload(payload statePayload) statePayload {
 	if len(payload.Fonts) == 0 {
 		payload.Fonts = []string{defaultFont}
 	}
@@ -695,27 +1275,95 @@ func loadPayload(statePath string) (statePayload, error) {
 	if !containsString(payload.Fonts, payload.Config.Font) {
 		payload.Config.Font = payload.Fonts[0]
 	}
-	return payload, nil
+	return payload
 }
 
 func main() {
 	statePath := flag.String("state", "", "Path to JSON state file")
+	standalone := flag.Bool("config", false, "Run without a state file, reading and writing the user's config directly")
+	configPath := flag.String("config-path", "", "Config file for --config (default: resolved like the timer CLI)")
+	fontDir := flag.String("font-dir", "", "Directory of figlet .flf fonts for --config (default: auto-detected)")
+	protocol := flag.Bool("protocol", false, "Read the state payload as JSON from stdin (or --state-fd) and print a JSON result to stdout; the UI uses the terminal directly")
+	stateFD := flag.Int("state-fd", -1, "With --protocol, read the state payload from this file descriptor instead of stdin")
+	allowSymlinks := flag.Bool("allow-symlinks", false, "Allow saving through a config file that is a symlink")
 	flag.Parse()
 
-	payload, err := loadPayload(*statePath)
+	command := flag.Arg(0)
+	policy := defaultConfigWritePolicy()
+	if (*standalone || command != "") && *configPath != "" {
+		// A path given on our own command line is trusted; one from a state
+		// payload is not.
+		if abs, err := filepath.Abs(*configPath); err == nil {
+			*configPath = abs
+			policy.dir = filepath.Dir(abs)
+		}
+	}
+	policy.allowSymlinks = *allowSymlinks
+
+	admin, err := loadAdminPolicy(systemPolicyPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load policy: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *protocol {
+		os.Exit(runProtocol(*stateFD, policy, admin))
+	}
+
+	switch command {
+	case "":
+	case "timer":
+		os.Exit(runTimerCommand(flag.Args()[1:], *configPath, *fontDir, policy, admin))
+	case "stopwatch":
+		os.Exit(runStopwatchCommand(flag.Args()[1:], *configPath, *fontDir, policy, admin))
+	case "pomodoro":
+		os.Exit(runPomodoroCommand(flag.Args()[1:], *configPath, *fontDir, policy, admin))
+	case "preset":
+		os.Exit(runPresetCommand(flag.Args()[1:], *configPath, *fontDir, policy, admin))
+	case "plan":
+		os.Exit(runPlanCommand(flag.Args()[1:], *configPath, *fontDir, policy, admin))
+	case "report":
+		os.Exit(runReportCommand(flag.Args()[1:], *configPath, *fontDir))
+	case "dashboard":
+		os.Exit(runDashboardCommand(flag.Args()[1:], *configPath, *fontDir, admin))
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n%s\n", command, commandUsage)
+		os.Exit(1)
+	}
+
+	var payload statePayload
+	if *standalone {
+		payload, err = loadStandalonePayload(*configPath, *fontDir)
+	} else {
+		payload, err = loadPayload(*statePath)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load state: %v\n", err)
 		os.Exit(1)
 	}
 
-	p := tea.NewProgram(newModel(payload), tea.WithAltScreen())
+	m := newModel(payload)
+	m.writePolicy = policy
+	m.applyAdminPolicy(admin)
+	m.offerDraftRestore()
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Settings UI failed: %v\n", err)
 		os.Exit(1)
 	}
 
-	m := finalModel.(model)
+	// Bubble Tea returns no model when it had to catch a panic itself.
+	final, ok := finalModel.(model)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Settings UI exited unexpectedly; unsaved changes are kept in a draft for the next launch")
+		os.Exit(1)
+	}
+	m = final
+	if m.crash.crashed() {
+		fmt.Fprintf(os.Stderr, "Settings UI crashed: %v\n%s\nUnsaved changes are kept in a draft for the next launch.\n", m.crash.err, m.crash.stack)
+		os.Exit(1)
+	}
 	if m.err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to save settings: %v\n", m.err)
 		os.Exit(1)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -24,6 +25,38 @@ func testPayload() statePayload {
 	}
 }
 
+// fakeClock stands in for wallNow, so a test decides when time passes.
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+// newFakeClock returns a fake clock and points each given clock model at
+// it, with its current run and its session starting at the fake time.
+func newFakeClock(clocks ...*clockModel) *fakeClock {
+	fake := &fakeClock{t: time.Unix(1000, 0)}
+	for _, c := range clocks {
+		c.now = fake.now
+		c.anchor = fake.t
+		c.session = sessionTracker{started: fake.t}
+	}
+	return fake
+}
+
+// runeKey is the message for typing key; " " is the space bar.
+func runeKey(key string) tea.KeyMsg {
+	if key == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(key)}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// press delivers msg to any of the package's models and returns the
+// updated model.
+func press[M tea.Model](m M, msg tea.KeyMsg) M {
+	updated, _ := m.Update(msg)
+	return updated.(M)
+}
+
 func TestEnterSelectsMainMenuAction(t *testing.T) {
 	m := newModel(testPayload())
 	if m.screen != screenMain {
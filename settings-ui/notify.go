@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+func runQuietly(name string, args ...string) bool {
+	cmd := exec.Command(name, args...)
+	return cmd.Run() == nil
+}
+
+func escapeAppleScriptString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+func escapePowerShellSingleQuoted(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+// sendSystemNotification mirrors sendSystemNotification in src/index.js:
+// each platform's notifiers are tried in turn until one succeeds.
+func sendSystemNotification(title string, message string) bool {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = "Timer"
+	}
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return false
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		group := fmt.Sprintf("cli-timer-%d", time.Now().UnixNano())
+		if runQuietly("terminal-notifier", "-title", title, "-message", message, "-group", group, "-ignoreDnD") {
+			return true
+		}
+		script := fmt.Sprintf(`display notification "%s" with title "%s"`, escapeAppleScriptString(message), escapeAppleScriptString(title))
+		return runQuietly("osascript", "-e", script)
+	case "windows":
+		balloon := strings.Join([]string{
+			"$ErrorActionPreference = 'Stop'",
+			"try {",
+			"  Add-Type -AssemblyName System.Windows.Forms",
+			"  Add-Type -AssemblyName System.Drawing",
+			"  $notify = New-Object System.Windows.Forms.NotifyIcon",
+			"  $notify.Icon = [System.Drawing.SystemIcons]::Information",
+			"  $notify.BalloonTipTitle = '" + escapePowerShellSingleQuoted(title) + "'",
+			"  $notify.BalloonTipText = '" + escapePowerShellSingleQuoted(message) + "'",
+			"  $notify.Visible = $true",
+			"  $notify.ShowBalloonTip(5000)",
+			"  Start-Sleep -Milliseconds 5500",
+			"  $notify.Dispose()",
+			"} catch { exit 1 }",
+		}, "; ")
+		return runQuietly("powershell", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-Sta", "-Command", balloon)
+	case "linux":
+		return runQuietly("termux-notification", "--title", title, "--content", message) ||
+			runQuietly("notify-send", "--app-name=cli-timer", "--urgency=critical", "--icon=dialog-information", title, message) ||
+			runQuietly("notify-send", title, message) ||
+			runQuietly("kdialog", "--title", title, "--passivepopup", message, "5") ||
+			runQuietly("zenity", "--notification", "--text="+title+": "+message)
+	}
+	return false
+}
+
+// playCue rings the bell once, for boundaries between the phases of a plan.
+func playCue(cfg config) {
+	if cfg.PlaySoundOnComplete {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+}
+
+func playCompletionAlarm(cfg config) {
+	if cfg.PlaySoundOnComplete {
+		fmt.Fprint(os.Stderr, strings.Repeat("\a", 5))
+	}
+}
+
+// notifyTimerFinished matches notifyTimerFinished in src/index.js.
+func notifyTimerFinished(cfg config, initialSeconds int) {
+	title := "Timer finished"
+	if initialSeconds > 0 {
+		title = fmt.Sprintf("Timer finished (%s)", formatHms(initialSeconds))
+	}
+	notifyFinished(title, cfg)
+}
+
+// notifyFinished sends the completion message under title and rings the
+// alarm, each when the config asks for it.
+func notifyFinished(title string, cfg config) {
+	if cfg.NotifyOnComplete {
+		message := cfg.CompletionMessage
+		if message == "" {
+			message = defaultCompletionMessage
+		}
+		sendSystemNotification(title, message)
+	}
+	playCompletionAlarm(cfg)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	numberStep      = 1
+	numberLargeStep = 5
+)
+
+// numberField is a whole-number setting edited on screenNumberEditor.
+type numberField struct {
+	title string
+	unit  string
+	min   int
+	max   int
+	field func(cfg *config) *int
+}
+
+var numberFields = map[string]numberField{
+	"pomodoroFocus": {"Focus length", "min", minPomodoroMinutes, maxPomodoroMinutes,
+		func(cfg *config) *int { return &cfg.Pomodoro.FocusMinutes }},
+	"pomodoroShortBreak": {"Short break", "min", minPomodoroMinutes, maxPomodoroMinutes,
+		func(cfg *config) *int { return &cfg.Pomodoro.ShortBreakMinutes }},
+	"pomodoroLongBreak": {"Long break", "min", minPomodoroMinutes, maxPomodoroMinutes,
+		func(cfg *config) *int { return &cfg.Pomodoro.LongBreakMinutes }},
+	"pomodoroCycles": {"Focus cycles before a long break", "", minPomodoroCycles, maxPomodoroCycles,
+		func(cfg *config) *int { return &cfg.Pomodoro.CyclesBeforeLongBreak }},
+	"historyRetention": {"History retention", "days", minHistoryRetentionDays, maxHistoryRetentionDays,
+		func(cfg *config) *int { return &cfg.HistoryRetentionDays }},
+}
+
+// messageField is a text setting edited on screenMessageEditor; normalize
+// cleans up the typed text before it is stored.
+type messageField struct {
+	title     string
+	field     func(cfg *config) *string
+	normalize func(value string) string
+}
+
+var messageFields = map[string]messageField{
+	"message": {"Completion message",
+		func(cfg *config) *string { return &cfg.CompletionMessage }, normalizeCompletionMessage},
+	"pomodoroFocusMessage": {"Message after focus",
+		func(cfg *config) *string { return &cfg.Pomodoro.FocusMessage }, normalizeCompletionMessage},
+	"pomodoroShortMessage": {"Message after a short break",
+		func(cfg *config) *string { return &cfg.Pomodoro.ShortBreakMessage }, normalizeCompletionMessage},
+	"pomodoroLongMessage": {"Message after a long break",
+		func(cfg *config) *string { return &cfg.Pomodoro.LongBreakMessage }, normalizeCompletionMessage},
+	"planDir": {"Plan directory",
+		func(cfg *config) *string { return &cfg.PlanDir }, strings.TrimSpace},
+}
+
+func numberText(value int, unit string) string {
+	if unit == "" {
+		return strconv.Itoa(value)
+	}
+	return fmt.Sprintf("%d %s", value, unit)
+}
+
+func newNumberInput() textinput.Model {
+	input := textinput.New()
+	input.CharLimit = 4
+	input.Blur()
+	return input
+}
+
+func (m *model) openNumberEditor(id string) {
+	field := numberFields[id]
+	m.numberTarget = id
+	m.numberInput.Prompt = field.title + ": "
+	m.numberInput.SetValue(strconv.Itoa(*field.field(&m.payload.Config)))
+	m.numberInput.CursorEnd()
+	m.numberInput.Focus()
+	m.screen = screenNumberEditor
+}
+
+func (m *model) openMessageEditor(id string) {
+	m.messageTarget = id
+	m.messageInput.Prompt = messageFields[id].title + ": "
+	m.messageInput.SetValue(*messageFields[id].field(&m.payload.Config))
+	m.messageInput.CursorEnd()
+	m.messageInput.Focus()
+	m.screen = screenMessageEditor
+}
+
+// editedNumber is the value in the number editor, or false while the input
+// does not hold an integer.
+func (m model) editedNumber() (int, bool) {
+	value, err := strconv.Atoi(strings.TrimSpace(m.numberInput.Value()))
+	return value, err == nil
+}
+
+func (m *model) adjustNumber(delta int) {
+	field := numberFields[m.numberTarget]
+	value, ok := m.editedNumber()
+	if !ok {
+		value = *field.field(&m.payload.Config)
+	}
+	value += delta
+	if value < field.min {
+		value = field.min
+	}
+	if value > field.max {
+		value = field.max
+	}
+	m.numberInput.SetValue(strconv.Itoa(value))
+	m.numberInput.CursorEnd()
+	m.err = nil
+}
+
+func (m *model) updateNumberEditor(msg tea.KeyMsg) tea.Cmd {
+	field := numberFields[m.numberTarget]
+	switch {
+	case isConfirmKey(msg):
+		value, ok := m.editedNumber()
+		if !ok {
+			m.err = fmt.Errorf("%s must be a whole number", strings.ToLower(field.title))
+			return nil
+		}
+		if value < field.min || value > field.max {
+			m.err = fmt.Errorf("%s must be between %d and %d", strings.ToLower(field.title), field.min, field.max)
+			return nil
+		}
+		*field.field(&m.payload.Config) = value
+		m.err = nil
+		m.numberInput.Blur()
+		m.screen = screenMain
+		m.refreshMenu()
+		return nil
+	case key.Matches(msg, m.keys.Increase):
+		m.adjustNumber(numberStep)
+		return nil
+	case key.Matches(msg, m.keys.Decrease):
+		m.adjustNumber(-numberStep)
+		return nil
+	case key.Matches(msg, m.keys.IncreaseMore):
+		m.adjustNumber(numberLargeStep)
+		return nil
+	case key.Matches(msg, m.keys.DecreaseMore):
+		m.adjustNumber(-numberLargeStep)
+		return nil
+	}
+	var cmd tea.Cmd
+	m.numberInput, cmd = m.numberInput.Update(msg)
+	return cmd
+}
+
+func (m model) numberEditorBindings() []key.Binding {
+	return []key.Binding{
+		withHelpDesc(m.keys.Confirm, "save"),
+		withHelpDesc(m.keys.Decrease, fmt.Sprintf("-%d", numberStep)),
+		withHelpDesc(m.keys.Increase, fmt.Sprintf("+%d", numberStep)),
+		withHelpDesc(m.keys.DecreaseMore, fmt.Sprintf("-%d", numberLargeStep)),
+		withHelpDesc(m.keys.IncreaseMore, fmt.Sprintf("+%d", numberLargeStep)),
+		m.keys.textBack(),
+	}
+}
+
+func (m model) numberEditorView(errorLine string, footer string) string {
+	field := numberFields[m.numberTarget]
+	return fmt.Sprintf("%s (%s to %s)\n\n%s%s\n\n%s",
+		field.title, numberText(field.min, field.unit), numberText(field.max, field.unit),
+		m.numberInput.View(), errorLine, footer)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var paletteTickRates = []int{minTickRateMs, 100, 250, 500, maxTickRateMs}
+
+// toggleSettings are jumped to rather than run from the palette; their
+// explicit On/Off values are separate palette commands.
+var toggleSettings = map[string]bool{
+	"randomFavorites":    true,
+	"center":             true,
+	"header":             true,
+	"controls":           true,
+	"notify":             true,
+	"sound":              true,
+	"dayFormat":          true,
+	"pomodoroAutoBreaks": true,
+	"pomodoroAutoFocus":  true,
+}
+
+type paletteCommand struct {
+	title string
+	hint  string
+	// setting is the menu entry a value command changes, so locked
+	// settings can refuse it.
+	setting string
+	run     func(m *model) tea.Cmd
+}
+
+func (c paletteCommand) Title() string       { return c.title }
+func (c paletteCommand) Description() string { return c.hint }
+func (c paletteCommand) FilterValue() string { return c.title }
+
+func boolSetter(field func(cfg *config) *bool, value bool) func(m *model) tea.Cmd {
+	return func(m *model) tea.Cmd {
+		*field(&m.payload.Config) = value
+		m.refreshMenu()
+		return nil
+	}
+}
+
+func buildPaletteCommands(m model) []paletteCommand {
+	var commands []paletteCommand
+
+	for _, item := range m.admin.markLocked(buildMenuItems(m.payload.Config, m.presets)) {
+		entry := item.(menuEntry)
+		hint := "Action"
+		if entry.section != sectionActions {
+			hint = fmt.Sprintf("%s setting | %s", sectionNames[entry.section], entry.description)
+		}
+		id := entry.id
+		commands = append(commands, paletteCommand{
+			title: entry.title,
+			hint:  hint,
+			run: func(m *model) tea.Cmd {
+				m.jumpToMenuEntry(id)
+				if toggleSettings[id] {
+					return nil
+				}
+				return m.applyMenuAction()
+			},
+		})
+	}
+
+	toggles := []struct {
+		id    string
+		title string
+		field func(cfg *config) *bool
+	}{
+		{"randomFavorites", "Random style from favorites", func(cfg *config) *bool { return &cfg.RandomFromFavorites }},
+		{"center", "Center display", func(cfg *config) *bool { return &cfg.CenterDisplay }},
+		{"header", "Show header", func(cfg *config) *bool { return &cfg.ShowHeader }},
+		{"controls", "Show controls", func(cfg *config) *bool { return &cfg.ShowControls }},
+		{"notify", "System notification", func(cfg *config) *bool { return &cfg.NotifyOnComplete }},
+		{"sound", "Completion sound/alarm", func(cfg *config) *bool { return &cfg.PlaySoundOnComplete }},
+		{"pomodoroAutoBreaks", "Auto-start breaks", func(cfg *config) *bool { return &cfg.Pomodoro.AutoStartBreaks }},
+		{"pomodoroAutoFocus", "Auto-start focus", func(cfg *config) *bool { return &cfg.Pomodoro.AutoStartFocus }},
+	}
+	for _, toggle := range toggles {
+		for _, value := range []bool{true, false} {
+			commands = append(commands, paletteCommand{
+				title:   fmt.Sprintf("%s: %s", toggle.title, boolText(value)),
+				hint:    "Set value",
+				setting: toggle.id,
+				run:     boolSetter(toggle.field, value),
+			})
+		}
+	}
+
+	for _, rate := range paletteTickRates {
+		value := rate
+		commands = append(commands, paletteCommand{
+			title:   fmt.Sprintf("Tick rate: %d ms", value),
+			hint:    "Set value",
+			setting: "tickRate",
+			run: func(m *model) tea.Cmd {
+				m.payload.Config.TickRateMs = value
+				m.refreshMenu()
+				return nil
+			},
+		})
+	}
+
+	for _, format := range []string{dayFormatDays, dayFormatHours} {
+		value := format
+		commands = append(commands, paletteCommand{
+			title:   "Day format: " + dayFormatLabel(value),
+			hint:    "Set value",
+			setting: "dayFormat",
+			run: func(m *model) tea.Cmd {
+				m.payload.Config.DayFormat = value
+				m.refreshMenu()
+				return nil
+			},
+		})
+	}
+
+	for _, preset := range m.presets {
+		value := preset
+		commands = append(commands, paletteCommand{
+			title:   "Keymap preset: " + value.Name,
+			hint:    keymapPresetEntry{preset: value}.Description(),
+			setting: "keymapPreset",
+			run: func(m *model) tea.Cmd {
+				m.payload.Config.Keybindings = value.Keybindings
+				m.refreshMenu()
+				return nil
+			},
+		})
+	}
+
+	for _, font := range m.payload.Fonts {
+		value := font
+		commands = append(commands, paletteCommand{
+			title:   "Font: " + value,
+			hint:    "Apply font",
+			setting: "font",
+			run: func(m *model) tea.Cmd {
+				m.chooseFont(value)
+				m.refreshMenu()
+				return nil
+			},
+		})
+	}
+
+	return commands
+}
+
+// filterPaletteCommands ranks commands against term with the same fuzzy
+// matcher the bubbles list uses for its own filtering.
+func filterPaletteCommands(commands []paletteCommand, term string) []list.Item {
+	if term == "" {
+		items := make([]list.Item, 0, len(commands))
+		for _, command := range commands {
+			items = append(items, command)
+		}
+		return items
+	}
+	targets := make([]string, 0, len(commands))
+	for _, command := range commands {
+		targets = append(targets, command.FilterValue())
+	}
+	ranks := list.DefaultFilter(term, targets)
+	items := make([]list.Item, 0, len(ranks))
+	for _, rank := range ranks {
+		items = append(items, commands[rank.Index])
+	}
+	return items
+}
+
+func (m *model) jumpToMenuEntry(id string) {
+	for _, item := range buildMenuItems(m.payload.Config, m.presets) {
+		entry := item.(menuEntry)
+		if entry.id != id {
+			continue
+		}
+		if entry.section != sectionActions && entry.section != m.section {
+			m.setSection(entry.section)
+		}
+		break
+	}
+	for idx, item := range m.menu.Items() {
+		if entry, ok := item.(menuEntry); ok && entry.id == id {
+			m.menu.Select(idx)
+			return
+		}
+	}
+}
+
+func (m *model) openPalette() {
+	m.paletteCommands = buildPaletteCommands(*m)
+	m.paletteInput.SetValue("")
+	m.paletteInput.Focus()
+	m.paletteList.SetItems(filterPaletteCommands(m.paletteCommands, ""))
+	m.paletteList.Select(0)
+	m.screen = screenPalette
+}
+
+func (m *model) closePalette() {
+	m.paletteInput.Blur()
+	m.screen = screenMain
+}
+
+func (m model) updatePalette(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "ctrl+p":
+		m.paletteList.CursorUp()
+		return m, nil
+	case "down", "ctrl+n":
+		m.paletteList.CursorDown()
+		return m, nil
+	case "pgup", "pgdown":
+		var cmd tea.Cmd
+		m.paletteList, cmd = m.paletteList.Update(msg)
+		return m, cmd
+	}
+
+	before := m.paletteInput.Value()
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	if m.paletteInput.Value() != before {
+		m.paletteList.SetItems(filterPaletteCommands(m.paletteCommands, m.paletteInput.Value()))
+		m.paletteList.Select(0)
+	}
+	return m, cmd
+}
+
+func (m *model) runSelectedPaletteCommand() tea.Cmd {
+	command, ok := m.paletteList.SelectedItem().(paletteCommand)
+	m.closePalette()
+	if !ok {
+		return nil
+	}
+	m.err = nil
+	m.notice = ""
+	if command.setting != "" && m.admin.menuEntryLocked(command.setting) {
+		m.jumpToMenuEntry(command.setting)
+		if entry, ok := m.menu.SelectedItem().(menuEntry); ok {
+			m.notice = lockedNotice(entry.title)
+		}
+		return nil
+	}
+	return command.run(m)
+}
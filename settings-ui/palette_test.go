@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typePalette(t *testing.T, m model, text string) model {
+	t.Helper()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(model)
+	if m.screen != screenPalette {
+		t.Fatalf("expected ctrl+p to open the palette, got %v", m.screen)
+	}
+	for _, r := range text {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(model)
+	}
+	return m
+}
+
+func TestPaletteJumpsToPauseKeyEditor(t *testing.T) {
+	m := typePalette(t, newModel(testPayload()), "pause")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+
+	if next.screen != screenKeyPicker || next.keyTarget != "pauseKey" {
+		t.Fatalf("expected pause key picker, got screen %v target %q", next.screen, next.keyTarget)
+	}
+	if next.section != sectionKeybindings {
+		t.Fatalf("expected Keybindings section, got %v", next.section)
+	}
+}
+
+func TestPaletteAppliesFontValue(t *testing.T) {
+	m := typePalette(t, newModel(testPayload()), "big")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+
+	if next.screen != screenMain {
+		t.Fatalf("expected palette to close, got %v", next.screen)
+	}
+	if next.payload.Config.Font != "Big" {
+		t.Fatalf("expected Big font, got %q", next.payload.Config.Font)
+	}
+}
+
+func TestSlashOpensPaletteAndEscCloses(t *testing.T) {
+	m := newModel(testPayload())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	next := updated.(model)
+	if next.screen != screenPalette {
+		t.Fatalf("expected / to open the palette, got %v", next.screen)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(model).screen != screenMain {
+		t.Fatalf("expected esc to close the palette")
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	configDirName        = ".cli-timer"
+	xdgConfigDirName     = "cli-timer"
+	configFileName       = "config.json"
+	fontDirEnv           = "CLI_TIMER_FONT_DIR"
+	figletFontExtension  = ".flf"
+	standardFontFileName = defaultFont + figletFontExtension
+)
+
+// resolveConfigDir mirrors resolveConfigDir in src/index.js: an existing
+// ~/.cli-timer always wins so upgrades never lose settings; otherwise an
+// absolute XDG_CONFIG_HOME selects $XDG_CONFIG_HOME/cli-timer.
+func resolveConfigDir(home string, xdgConfigHome string) string {
+	legacy := filepath.Join(home, configDirName)
+	if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+		return legacy
+	}
+	if xdgConfigHome != "" && filepath.IsAbs(xdgConfigHome) {
+		return filepath.Join(xdgConfigHome, xdgConfigDirName)
+	}
+	return legacy
+}
+
+func defaultConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolveConfigDir(home, os.Getenv("XDG_CONFIG_HOME")), configFileName), nil
+}
+
+// fontDirCandidates lists where a figlet fonts directory may live, most
+// specific first: the environment override, the npm package layout around a
+// prebuilt binary, and common system install locations.
+func fontDirCandidates() []string {
+	var candidates []string
+	if dir := os.Getenv(fontDirEnv); dir != "" {
+		candidates = append(candidates, dir)
+	}
+	if exe, err := os.Executable(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+		exeDir := filepath.Dir(exe)
+		candidates = append(candidates,
+			filepath.Join(exeDir, "fonts"),
+			filepath.Join(exeDir, "..", "share", "cli-timer", "fonts"),
+			// settings-ui/prebuilt/<target>/ inside the npm package.
+			filepath.Join(exeDir, "..", "..", "..", "node_modules", "figlet", "fonts"),
+			// Sibling of the package when installed globally.
+			filepath.Join(exeDir, "..", "..", "..", "..", "figlet", "fonts"),
+		)
+	}
+	return append(candidates,
+		"/usr/share/figlet",
+		"/usr/share/figlet/fonts",
+		"/usr/local/share/figlet",
+		"/opt/homebrew/share/figlet",
+	)
+}
+
+func findFontDir(candidates []string) string {
+	for _, dir := range candidates {
+		if _, err := os.Stat(filepath.Join(dir, standardFontFileName)); err == nil {
+			return filepath.Clean(dir)
+		}
+	}
+	return ""
+}
+
+// listFonts returns the font names in dir sorted case-insensitively, like
+// getAllFonts on the JS side.
+func listFonts(dir string) []string {
+	if dir == "" {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+figletFontExtension))
+	if err != nil {
+		return nil
+	}
+	fonts := make([]string, 0, len(paths))
+	for _, path := range paths {
+		fonts = append(fonts, strings.TrimSuffix(filepath.Base(path), figletFontExtension))
+	}
+	sort.SliceStable(fonts, func(i, j int) bool {
+		return strings.ToLower(fonts[i]) < strings.ToLower(fonts[j])
+	})
+	return fonts
+}
+
+// readConfigFile loads a config file written by either side. A missing file
+// yields the defaults; fields absent from the file keep their defaults.
+func readConfigFile(path string) (config, error) {
+	cfg := defaultConfig()
+	text, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return config{}, err
+	}
+	if err := json.Unmarshal(text, &cfg); err != nil {
+		return config{}, err
+	}
+	return normalizeConfig(cfg), nil
+}
+
+// loadStandalonePayload builds the payload the JS wrapper would have written,
+// so the binary can run without Node.
+func loadStandalonePayload(configPath string, fontDir string) (statePayload, error) {
+	if configPath == "" {
+		resolved, err := defaultConfigPath()
+		if err != nil {
+			return statePayload{}, err
+		}
+		configPath = resolved
+	}
+	if fontDir == "" {
+		fontDir = findFontDir(fontDirCandidates())
+	}
+	cfg, err := readConfigFile(configPath)
+	if err != nil {
+		return statePayload{}, err
+	}
+	return finishPayload(statePayload{
+		ConfigPath: configPath,
+		Config:     cfg,
+		Fonts:      listFonts(fontDir),
+		FontDir:    fontDir,
+	}), nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolveConfigDirPrefersExistingLegacyDir(t *testing.T) {
+	home := t.TempDir()
+	xdg := filepath.Join(t.TempDir(), "xdg")
+
+	if got := resolveConfigDir(home, xdg); got != filepath.Join(xdg, "cli-timer") {
+		t.Fatalf("expected XDG dir without a legacy dir, got %q", got)
+	}
+	if got := resolveConfigDir(home, "relative/xdg"); got != filepath.Join(home, ".cli-timer") {
+		t.Fatalf("expected relative XDG_CONFIG_HOME to be ignored, got %q", got)
+	}
+	if err := os.Mkdir(filepath.Join(home, ".cli-timer"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolveConfigDir(home, xdg); got != filepath.Join(home, ".cli-timer") {
+		t.Fatalf("expected existing legacy dir to win, got %q", got)
+	}
+}
+
+func TestLoadStandalonePayloadReadsConfigAndFonts(t *testing.T) {
+	fontDir := t.TempDir()
+	for _, name := range []string{"Standard.flf", "big.flf", "Banner.flf", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(fontDir, name), []byte("flf2a$ 1 1 1 -1 0\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"font":"big","tickRateMs":250}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := loadStandalonePayload(configPath, fontDir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !reflect.DeepEqual(payload.Fonts, []string{"Banner", "big", "Standard"}) {
+		t.Fatalf("unexpected fonts %v", payload.Fonts)
+	}
+	if payload.Config.Font != "big" || payload.Config.TickRateMs != 250 {
+		t.Fatalf("expected config values from file, got %+v", payload.Config)
+	}
+	if !payload.Config.CenterDisplay || !payload.Config.NotifyOnComplete {
+		t.Fatalf("expected missing fields to keep their defaults, got %+v", payload.Config)
+	}
+}
+
+func TestLoadStandalonePayloadWithoutConfigFile(t *testing.T) {
+	payload, err := loadStandalonePayload(filepath.Join(t.TempDir(), "missing", "config.json"), t.TempDir())
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !reflect.DeepEqual(payload.Fonts, []string{defaultFont}) || payload.Config.TickRateMs != defaultTickRateMs {
+		t.Fatalf("expected defaults, got %+v", payload)
+	}
+}
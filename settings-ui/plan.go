@@ -0,0 +1,494 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cli-timer-settings-ui/duration"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	maxPlanRepeat = 100
+	maxPlanPhases = 1000
+	planBarWidth  = 40
+)
+
+var planExtensions = []string{".yaml", ".yml", ".json"}
+
+// planFile is an interval plan as written by the user:
+//
+//	name: Tabata
+//	phases:
+//	  - name: Warm up
+//	    duration: 5 min
+//	    color: yellow
+//	  - repeat: 8
+//	    phases:
+//	      - {name: Work, duration: 20s, color: red, message: Go!}
+//	      - {name: Rest, duration: 10s, color: green}
+type planFile struct {
+	Name   string     `json:"name" yaml:"name"`
+	Repeat int        `json:"repeat" yaml:"repeat"`
+	Phases []planStep `json:"phases" yaml:"phases"`
+}
+
+// planStep is either a phase with a duration or a group of steps; both can
+// repeat. A group's color and message apply to steps that set none.
+type planStep struct {
+	Name     string     `json:"name" yaml:"name"`
+	Duration string     `json:"duration" yaml:"duration"`
+	Repeat   int        `json:"repeat" yaml:"repeat"`
+	Color    string     `json:"color" yaml:"color"`
+	Message  string     `json:"message" yaml:"message"`
+	Phases   []planStep `json:"phases" yaml:"phases"`
+}
+
+// planPhase is one phase of the expanded plan, in playing order.
+type planPhase struct {
+	name    string
+	seconds int
+	color   string
+	message string
+	// round and rounds count the innermost repeat the phase belongs to;
+	// rounds is 0 when it does not repeat.
+	round  int
+	rounds int
+}
+
+type plan struct {
+	name   string
+	phases []planPhase
+}
+
+func (p plan) totalSeconds() int {
+	total := 0
+	for _, phase := range p.phases {
+		total += phase.seconds
+	}
+	return total
+}
+
+var planColorNames = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
+var (
+	hexColorPattern  = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	ansiColorPattern = regexp.MustCompile(`^\d{1,3}$`)
+)
+
+// planColor turns a color name, ANSI number (0-255) or #hex value into a
+// terminal color. Empty means the terminal's own color.
+func planColor(value string) (lipgloss.Color, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", nil
+	}
+	if ansi, ok := planColorNames[value]; ok {
+		return lipgloss.Color(ansi), nil
+	}
+	if hexColorPattern.MatchString(value) {
+		return lipgloss.Color(value), nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && ansiColorPattern.MatchString(value) && n <= 255 {
+		return lipgloss.Color(value), nil
+	}
+	return "", fmt.Errorf("unknown color %q (use a name such as red, an ANSI number 0-255 or #rrggbb)", value)
+}
+
+// parsePlan reads a plan from YAML or JSON text; ext picks the format.
+func parsePlan(text []byte, ext string) (plan, error) {
+	var file planFile
+	if strings.ToLower(ext) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&file); err != nil {
+			return plan{}, err
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(text))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil {
+			return plan{}, err
+		}
+	}
+
+	if len(file.Phases) == 0 {
+		return plan{}, errors.New("plan has no phases")
+	}
+	root := planStep{Repeat: file.Repeat, Phases: file.Phases}
+	phases, err := expandPlanStep(root, "plan")
+	if err != nil {
+		return plan{}, err
+	}
+	name := strings.TrimSpace(file.Name)
+	if name == "" {
+		name = "Plan"
+	}
+	return plan{name: name, phases: phases}, nil
+}
+
+func expandPlanStep(step planStep, where string) ([]planPhase, error) {
+	if step.Name != "" {
+		where = fmt.Sprintf("%s (%q)", where, step.Name)
+	}
+	repeat := step.Repeat
+	if repeat == 0 {
+		repeat = 1
+	}
+	if repeat < 0 || repeat > maxPlanRepeat {
+		return nil, fmt.Errorf("%s: repeat must be between 1 and %d", where, maxPlanRepeat)
+	}
+	if _, err := planColor(step.Color); err != nil {
+		return nil, fmt.Errorf("%s: %w", where, err)
+	}
+
+	var once []planPhase
+	switch {
+	case len(step.Phases) > 0 && strings.TrimSpace(step.Duration) != "":
+		return nil, fmt.Errorf("%s: a step has either a duration or phases, not both", where)
+	case len(step.Phases) > 0:
+		for idx, child := range step.Phases {
+			expanded, err := expandPlanStep(child, fmt.Sprintf("%s phase %d", where, idx+1))
+			if err != nil {
+				return nil, err
+			}
+			for i := range expanded {
+				if expanded[i].color == "" {
+					expanded[i].color = step.Color
+				}
+				if expanded[i].message == "" {
+					expanded[i].message = step.Message
+				}
+			}
+			once = append(once, expanded...)
+		}
+	case strings.TrimSpace(step.Duration) != "":
+		spec, err := duration.Parse(strings.Fields(step.Duration), time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", where, err)
+		}
+		if spec.IsTarget() {
+			return nil, fmt.Errorf("%s: plan phases take a length of time, not a target", where)
+		}
+		name := strings.TrimSpace(step.Name)
+		if name == "" {
+			name = "Phase"
+		}
+		once = []planPhase{{
+			name:    name,
+			seconds: spec.Seconds(time.Now()),
+			color:   step.Color,
+			message: normalizeCompletionMessage(step.Message),
+		}}
+	default:
+		return nil, fmt.Errorf("%s: missing duration", where)
+	}
+
+	if len(once)*repeat > maxPlanPhases {
+		return nil, fmt.Errorf("%s: plan expands to more than %d phases", where, maxPlanPhases)
+	}
+	phases := make([]planPhase, 0, len(once)*repeat)
+	for round := 1; round <= repeat; round++ {
+		for _, phase := range once {
+			if repeat > 1 && phase.rounds == 0 {
+				phase.round, phase.rounds = round, repeat
+			}
+			phases = append(phases, phase)
+		}
+	}
+	return phases, nil
+}
+
+func readPlanFile(path string) (plan, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return plan{}, err
+	}
+	p, err := parsePlan(text, filepath.Ext(path))
+	if err != nil {
+		return plan{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return p, nil
+}
+
+// resolvePlanDir is the configured plan directory, or plans/ next to the
+// config file. A leading ~ is the home directory.
+func resolvePlanDir(cfg config, configPath string) string {
+	dir := strings.TrimSpace(cfg.PlanDir)
+	if dir == "" {
+		return filepath.Join(filepath.Dir(configPath), "plans")
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, dir[1:])
+		}
+	}
+	return dir
+}
+
+func planDirLabel(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "plans/ next to the config file"
+	}
+	return value
+}
+
+// findPlanFile takes a path, or a plan name looked up in dir with any of
+// the plan extensions.
+func findPlanFile(nameOrPath string, dir string) (string, error) {
+	if info, err := os.Stat(nameOrPath); err == nil && !info.IsDir() {
+		return nameOrPath, nil
+	}
+	for _, ext := range planExtensions {
+		path := filepath.Join(dir, nameOrPath+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no plan %q in %s", nameOrPath, dir)
+}
+
+// listPlanNames returns the plan files in dir without their extension.
+func listPlanNames(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if !entry.IsDir() && containsString(planExtensions, strings.ToLower(ext)) {
+			names = append(names, strings.TrimSuffix(entry.Name(), ext))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// planModel plays the phases of a plan back to back on the Go clock, with
+// an overview of where the plan stands under the clock.
+type planModel struct {
+	clock clockModel
+	plan  plan
+	index int
+}
+
+func newPlanModel(p plan, payload statePayload) planModel {
+	m := planModel{
+		clock: newClockModel("timer", 0, payload),
+		plan:  p,
+	}
+	m.startPhase(0)
+	return m
+}
+
+func (m planModel) last() bool {
+	return m.index == len(m.plan.phases)-1
+}
+
+func (m *planModel) startPhase(index int) {
+	m.index = index
+	phase := m.plan.phases[index]
+	m.clock.baseSeconds = phase.seconds
+	m.clock.title = fmt.Sprintf("%s | %s", m.plan.name, phase.name)
+	if m.last() {
+		title := fmt.Sprintf("%s finished (%s)", m.plan.name, formatHms(m.plan.totalSeconds()))
+		m.clock.notify = func(cfg config, _ int) { notifyFinished(title, cfg) }
+	} else {
+		m.clock.notify = func(cfg config, _ int) { playCue(cfg) }
+	}
+	m.clock.restart()
+}
+
+// advance starts the next phase from the moment the current one ran out
+// rather than from the tick that noticed, so phase boundaries do not drift
+// by up to a tick each. pausedElapsed holds the elapsed time once the clock
+// is done, so the overshoot past baseSeconds is how late the tick was.
+func (m *planModel) advance() tea.Cmd {
+	overshoot := m.clock.pausedElapsed - time.Duration(m.clock.baseSeconds)*time.Second
+	ranOut := m.clock.doneAt.Add(-overshoot)
+	m.startPhase(m.index + 1)
+	m.clock.anchor = ranOut
+	return m.clock.refreshDone()
+}
+
+// historyEntry logs the whole plan as one session; it is complete once the
+// last phase has run out.
+func (m planModel) historyEntry() historyEntry {
+	entry := m.clock.historyEntry(m.plan.name)
+	entry.Mode = "plan"
+	entry.PlannedSeconds = m.plan.totalSeconds()
+	entry.Completed = m.clock.done && m.last()
+	return entry
+}
+
+// remainingSeconds is what is left of the current phase and all later ones.
+func (m planModel) remainingSeconds() int {
+	total := m.clock.displaySeconds()
+	for _, phase := range m.plan.phases[m.index+1:] {
+		total += phase.seconds
+	}
+	return total
+}
+
+func (m planModel) Init() tea.Cmd {
+	return m.clock.Init()
+}
+
+func (m planModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && m.clock.done && m.last() {
+		// Once the plan is over, restart plays it again from the top.
+		if clockKeyToken(key) == m.clock.cfg.Keybindings.RestartKey {
+			m.startPhase(0)
+			return m, nil
+		}
+	}
+	updated, cmd := m.clock.Update(msg)
+	m.clock = updated.(clockModel)
+	// A late tick, such as the first one after a suspend, may have run past
+	// several phases.
+	for m.clock.done && !m.last() {
+		cmd = tea.Batch(cmd, m.advance())
+	}
+	return m, cmd
+}
+
+func (m planModel) overviewLines() []string {
+	phase := m.plan.phases[m.index]
+	dayFormat := m.clock.cfg.DayFormat
+	current := fmt.Sprintf("Phase %d of %d: %s", m.index+1, len(m.plan.phases), phase.name)
+	if phase.rounds > 0 {
+		current += fmt.Sprintf(" (round %d/%d)", phase.round, phase.rounds)
+	}
+	lines := []string{fmt.Sprintf("%s | Total remaining %s", current, formatClock(m.remainingSeconds(), dayFormat))}
+
+	total := m.plan.totalSeconds()
+	filled := 0
+	if total > 0 {
+		filled = (total - m.remainingSeconds()) * planBarWidth / total
+	}
+	lines = append(lines, "["+strings.Repeat("=", filled)+strings.Repeat("-", planBarWidth-filled)+"]")
+
+	if m.last() {
+		lines = append(lines, "Last phase")
+	} else {
+		next := m.plan.phases[m.index+1]
+		lines = append(lines, fmt.Sprintf("Next: %s (%s)", next.name, formatClock(next.seconds, dayFormat)))
+	}
+	if phase.message != "" && !m.clock.done {
+		lines = append(lines, phase.message)
+	}
+	return lines
+}
+
+func (m planModel) View() string {
+	overview := m.overviewLines()
+	clock := m.clock
+	height := clock.height
+	if height <= 0 {
+		height = fallbackFrameHeight
+	}
+	if height-len(overview)-1 >= 3 {
+		clock.height = height - len(overview) - 1
+	}
+	view := clock.View()
+	if color, _ := planColor(m.plan.phases[m.index].color); color != "" {
+		view = lipgloss.NewStyle().Foreground(color).Render(view)
+	}
+	lines := []string{view, ""}
+	for _, line := range overview {
+		lines = append(lines, clipLine(line, clock.width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runPlanCommand plays `plan <name or file>`; without arguments it lists the
+// plans in the plan directory.
+func runPlanCommand(args []string, configPath string, fontDir string, policy configWritePolicy, admin adminPolicy) int {
+	tagValues, args, err := extractFlag(args, "tag")
+	var tags []string
+	if err == nil {
+		tags, err = normalizeTags(tagValues)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	payload, err := loadStandalonePayload(configPath, fontDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	payload.Config = admin.apply(payload.Config)
+	dir := resolvePlanDir(payload.Config, payload.ConfigPath)
+
+	if len(args) == 0 {
+		names := listPlanNames(dir)
+		if len(names) == 0 {
+			fmt.Printf("No plans in %s\n", dir)
+			return 0
+		}
+		fmt.Printf("Plans in %s:\n", dir)
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return 0
+	}
+	if len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n\n%s\n", strings.Join(args[1:], " "), commandUsage)
+		return 1
+	}
+
+	path, err := findPlanFile(args[0], dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	p, err := readPlanFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid plan %v\n", err)
+		return 1
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Fprintln(os.Stderr, "Plans require an interactive terminal (TTY).")
+		return 1
+	}
+
+	m := newPlanModel(p, payload)
+	m.clock.writePolicy = policy
+	m.clock.admin = admin
+	m.clock.tags = tags
+	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Plan failed: %v\n", err)
+		return 1
+	}
+	if final, ok := final.(planModel); ok {
+		recordSessions(payload, final.historyEntry())
+	}
+	return 0
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const tabataPlan = `
+name: Tabata
+phases:
+  - name: Warm up
+    duration: 1 min
+    color: yellow
+  - repeat: 2
+    color: red
+    message: Go!
+    phases:
+      - {name: Work, duration: 20s}
+      - {name: Rest, duration: 0:10, color: green, message: Breathe}
+`
+
+func TestParsePlanExpandsRepeatsAndInheritsGroupFields(t *testing.T) {
+	p, err := parsePlan([]byte(tabataPlan), ".yaml")
+	if err != nil {
+		t.Fatalf("parsePlan failed: %v", err)
+	}
+	want := []planPhase{
+		{name: "Warm up", seconds: 60, color: "yellow"},
+		{name: "Work", seconds: 20, color: "red", message: "Go!", round: 1, rounds: 2},
+		{name: "Rest", seconds: 10, color: "green", message: "Breathe", round: 1, rounds: 2},
+		{name: "Work", seconds: 20, color: "red", message: "Go!", round: 2, rounds: 2},
+		{name: "Rest", seconds: 10, color: "green", message: "Breathe", round: 2, rounds: 2},
+	}
+	if p.name != "Tabata" || len(p.phases) != len(want) {
+		t.Fatalf("unexpected plan %+v", p)
+	}
+	for idx, phase := range want {
+		if p.phases[idx] != phase {
+			t.Fatalf("phase %d = %+v, want %+v", idx+1, p.phases[idx], phase)
+		}
+	}
+	if p.totalSeconds() != 120 {
+		t.Fatalf("expected 120 seconds in total, got %d", p.totalSeconds())
+	}
+}
+
+func TestParsePlanReadsJSON(t *testing.T) {
+	p, err := parsePlan([]byte(`{"phases":[{"name":"Boil","duration":"PT4M","repeat":3}]}`), ".json")
+	if err != nil {
+		t.Fatalf("parsePlan failed: %v", err)
+	}
+	if p.name != "Plan" || len(p.phases) != 3 || p.phases[2].seconds != 240 || p.phases[2].round != 3 {
+		t.Fatalf("unexpected plan %+v", p)
+	}
+}
+
+func TestParsePlanErrors(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{`phases: []`, "plan has no phases"},
+		{`phases: [{name: Work}]`, `plan phase 1 ("Work"): missing duration`},
+		{`phases: [{name: Work, duration: 5 parsecs}]`, `plan phase 1 ("Work"): unknown unit`},
+		{`phases: [{duration: until 17:30}]`, "not a target"},
+		{`phases: [{duration: 5s, color: mauve}]`, `unknown color "mauve"`},
+		{`phases: [{duration: 5s, repeat: 500}]`, "repeat must be between 1 and 100"},
+		{`phases: [{duration: 5s, phases: [{duration: 1s}]}]`, "either a duration or phases"},
+		{`phases: [{duration: 5s, colour: red}]`, "field colour not found"},
+	}
+	for _, tc := range cases {
+		_, err := parsePlan([]byte(tc.text), ".yml")
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("parsePlan(%q) error = %v, want %q", tc.text, err, tc.want)
+		}
+	}
+}
+
+func TestFindPlanFileByNameOrPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tabata.yml")
+	if err := os.WriteFile(path, []byte(tabataPlan), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := findPlanFile("tabata", dir); err != nil || got != path {
+		t.Fatalf("findPlanFile by name = %q, %v", got, err)
+	}
+	if got, err := findPlanFile(path, t.TempDir()); err != nil || got != path {
+		t.Fatalf("findPlanFile by path = %q, %v", got, err)
+	}
+	if _, err := findPlanFile("missing", dir); err == nil {
+		t.Fatalf("expected an error for a missing plan")
+	}
+	if names := listPlanNames(dir); len(names) != 1 || names[0] != "tabata" {
+		t.Fatalf("unexpected plan list %v", names)
+	}
+}
+
+func TestResolvePlanDir(t *testing.T) {
+	configPath := filepath.Join("cfg", "config.json")
+	if got := resolvePlanDir(config{}, configPath); got != filepath.Join("cfg", "plans") {
+		t.Fatalf("expected plans next to the config, got %q", got)
+	}
+	if got := resolvePlanDir(config{PlanDir: "/srv/plans"}, configPath); got != "/srv/plans" {
+		t.Fatalf("expected the configured directory, got %q", got)
+	}
+}
+
+func TestPlanModelAdvancesThroughPhases(t *testing.T) {
+	p, err := parsePlan([]byte(tabataPlan), ".yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newPlanModel(p, testPayload())
+	fake := newFakeClock(&m.clock)
+
+	if got := m.overviewLines()[0]; got != "Phase 1 of 5: Warm up | Total remaining 00:02:00" {
+		t.Fatalf("unexpected overview %q", got)
+	}
+
+	fake.t = fake.t.Add(60 * time.Second)
+	updated, _ := m.Update(clockTickMsg(fake.t))
+	m = updated.(planModel)
+	if m.index != 1 || m.clock.done || m.clock.title != "Tabata | Work" {
+		t.Fatalf("expected Work to start after the warm up, got index %d title %q", m.index, m.clock.title)
+	}
+	if got := m.overviewLines()[0]; got != "Phase 2 of 5: Work (round 1/2) | Total remaining 00:01:00" {
+		t.Fatalf("unexpected overview %q", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		fake.t = fake.t.Add(time.Duration(m.clock.baseSeconds) * time.Second)
+		updated, _ = m.Update(clockTickMsg(fake.t))
+		m = updated.(planModel)
+	}
+	if m.index != 4 || !m.last() {
+		t.Fatalf("expected the last phase, got index %d", m.index)
+	}
+	fake.t = fake.t.Add(10 * time.Second)
+	updated, _ = m.Update(clockTickMsg(fake.t))
+	m = updated.(planModel)
+	if !m.clock.done || m.remainingSeconds() != 0 {
+		t.Fatalf("expected the plan to finish")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if m = updated.(planModel); m.index != 0 || m.clock.done {
+		t.Fatalf("expected restart to play the plan from the top, got index %d", m.index)
+	}
+}
+
+func TestPlanModelStartsEachPhaseWhenThePreviousRanOut(t *testing.T) {
+	p, err := parsePlan([]byte(tabataPlan), ".yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newPlanModel(p, testPayload())
+	fake := newFakeClock(&m.clock)
+	start := fake.t
+
+	// The tick that ends the warm up arrives 700ms late.
+	fake.t = start.Add(60*time.Second + 700*time.Millisecond)
+	updated, _ := m.Update(clockTickMsg(fake.t))
+	m = updated.(planModel)
+	if m.index != 1 || !m.clock.anchor.Equal(start.Add(60*time.Second)) {
+		t.Fatalf("expected Work to start at the end of the warm up, got index %d anchored at %v", m.index, m.clock.anchor)
+	}
+
+	fake.t = start.Add(80 * time.Second)
+	updated, _ = m.Update(clockTickMsg(fake.t))
+	m = updated.(planModel)
+	if m.index != 2 || m.clock.title != "Tabata | Rest" {
+		t.Fatalf("expected Rest to start 20s after the warm up, got index %d", m.index)
+	}
+
+	// A tick after a suspend skips the phases that ran out meanwhile.
+	fake.t = start.Add(95 * time.Second)
+	updated, _ = m.Update(clockTickMsg(fake.t))
+	m = updated.(planModel)
+	if m.index != 3 || m.clock.displaySeconds() != 15 {
+		t.Fatalf("expected the second Work with 15s left, got index %d with %ds", m.index, m.clock.displaySeconds())
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// adminPolicy pins settings to values chosen by an administrator. It is read
+// from a system-wide file the user cannot edit, never from the state payload.
+//
+//	{"locked": {"notifyOnComplete": false, "font": "Standard",
+//	            "keybindings": {"pauseKey": "p"}}}
+type adminPolicy struct {
+	// locked maps dotted config paths, as reported by changedFields, to
+	// their pinned JSON values.
+	locked map[string]interface{}
+}
+
+type adminPolicyFile struct {
+	Locked map[string]interface{} `json:"locked"`
+}
+
+// menuPolicyFields lists the config paths each menu entry edits. An entry is
+// locked when any of them is.
+var menuPolicyFields = map[string][]string{
+	"font":            {"font"},
+	"randomFavorites": {"randomFromFavorites"},
+	// Not a menu entry: guards starring fonts in the picker.
+	"favoriteFonts":    {"favoriteFonts"},
+	"center":           {"centerDisplay"},
+	"header":           {"showHeader"},
+	"controls":         {"showControls"},
+	"tickRate":         {"tickRateMs"},
+	"dayFormat":        {"dayFormat"},
+	"timeZone":         {"timeZone"},
+	"planDir":          {"planDir"},
+	"presets":          {"presets"},
+	"historyRetention": {"historyRetentionDays"},
+	"message":          {"completionMessage"},
+	"notify":           {"notifyOnComplete"},
+	"sound":            {"playSoundOnComplete"},
+	"keymapPreset": {
+		"keybindings.pauseKey", "keybindings.pauseAltKey", "keybindings.restartKey",
+		"keybindings.styleKey", "keybindings.exitKey", "keybindings.exitAltKey",
+		"keybindings.lapKey", "keybindings.deleteKey",
+	},
+	"pauseKey":    {"keybindings.pauseKey"},
+	"pauseAltKey": {"keybindings.pauseAltKey"},
+	"restartKey":  {"keybindings.restartKey"},
+	"styleKey":    {"keybindings.styleKey"},
+	"exitKey":     {"keybindings.exitKey"},
+	"exitAltKey":  {"keybindings.exitAltKey"},
+	"lapKey":      {"keybindings.lapKey"},
+	"deleteKey":   {"keybindings.deleteKey"},
+
+	"pomodoroFocus":        {"pomodoro.focusMinutes"},
+	"pomodoroShortBreak":   {"pomodoro.shortBreakMinutes"},
+	"pomodoroLongBreak":    {"pomodoro.longBreakMinutes"},
+	"pomodoroCycles":       {"pomodoro.cyclesBeforeLongBreak"},
+	"pomodoroAutoBreaks":   {"pomodoro.autoStartBreaks"},
+	"pomodoroAutoFocus":    {"pomodoro.autoStartFocus"},
+	"pomodoroFocusMessage": {"pomodoro.focusMessage"},
+	"pomodoroShortMessage": {"pomodoro.shortBreakMessage"},
+	"pomodoroLongMessage":  {"pomodoro.longBreakMessage"},
+}
+
+func systemPolicyPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "cli-timer", "policy.json")
+	}
+	return "/etc/cli-timer/policy.json"
+}
+
+// loadAdminPolicy reads the policy file at path. A missing file means nothing
+// is locked; an unreadable or invalid one is an error, so a broken policy
+// never silently unlocks everything.
+func loadAdminPolicy(path string) (adminPolicy, error) {
+	text, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return adminPolicy{}, nil
+	}
+	if err != nil {
+		return adminPolicy{}, fmt.Errorf("policy %s: %w", path, err)
+	}
+	var file adminPolicyFile
+	if err := json.Unmarshal(text, &file); err != nil {
+		return adminPolicy{}, fmt.Errorf("policy %s: %w", path, err)
+	}
+
+	locked := map[string]interface{}{}
+	flattenJSON("", map[string]interface{}(file.Locked), locked)
+	known := configPaths(defaultConfig())
+	for name := range locked {
+		if _, ok := known[name]; !ok {
+			return adminPolicy{}, fmt.Errorf("policy %s: unknown setting %q", path, name)
+		}
+	}
+	policy := adminPolicy{locked: locked}
+	if _, err := policy.tryApply(defaultConfig()); err != nil {
+		return adminPolicy{}, fmt.Errorf("policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+func (p adminPolicy) tryApply(cfg config) (config, error) {
+	if len(p.locked) == 0 {
+		return cfg, nil
+	}
+	var generic map[string]interface{}
+	text, _ := json.Marshal(cfg)
+	_ = json.Unmarshal(text, &generic)
+	for name, value := range p.locked {
+		parts := strings.Split(name, ".")
+		object := generic
+		for _, part := range parts[:len(parts)-1] {
+			object = object[part].(map[string]interface{})
+		}
+		object[parts[len(parts)-1]] = value
+	}
+	text, _ = json.Marshal(generic)
+	var next config
+	if err := json.Unmarshal(text, &next); err != nil {
+		return cfg, err
+	}
+	return normalizeConfig(next), nil
+}
+
+// apply returns cfg with every locked setting forced to its pinned value.
+func (p adminPolicy) apply(cfg config) config {
+	next, _ := p.tryApply(cfg)
+	return next
+}
+
+func (p adminPolicy) menuEntryLocked(id string) bool {
+	for _, name := range menuPolicyFields[id] {
+		if _, ok := p.locked[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p adminPolicy) markLocked(items []list.Item) []list.Item {
+	for idx, item := range items {
+		if entry, ok := item.(menuEntry); ok && p.menuEntryLocked(entry.id) {
+			entry.locked = true
+			items[idx] = entry
+		}
+	}
+	return items
+}
+
+func lockedNotice(title string) string {
+	return fmt.Sprintf("%s is locked by your administrator", title)
+}
+
+// applyAdminPolicy installs the policy and forces the loaded config to
+// comply, so the session starts from what will actually be saved.
+func (m *model) applyAdminPolicy(policy adminPolicy) {
+	m.admin = policy
+	m.payload.Config = policy.apply(m.payload.Config)
+	m.refreshMenu()
+}
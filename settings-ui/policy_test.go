@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func writePolicyFile(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func testLabPolicy(t *testing.T) adminPolicy {
+	t.Helper()
+	policy, err := loadAdminPolicy(writePolicyFile(t, `{"locked": {"notifyOnComplete": false, "font": "Big", "keybindings": {"pauseKey": "x"}}}`))
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	return policy
+}
+
+func TestLoadAdminPolicyMissingFileLocksNothing(t *testing.T) {
+	policy, err := loadAdminPolicy(filepath.Join(t.TempDir(), "policy.json"))
+	if err != nil || len(policy.locked) != 0 {
+		t.Fatalf("expected empty policy, got %+v err=%v", policy, err)
+	}
+}
+
+func TestLoadAdminPolicyRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]string{
+		"malformed":     `{"locked": `,
+		"unknown field": `{"locked": {"colour": "red"}}`,
+		"wrong type":    `{"locked": {"tickRateMs": "fast"}}`,
+	}
+	for name, text := range cases {
+		if _, err := loadAdminPolicy(writePolicyFile(t, text)); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestAdminPolicyApplyPinsLockedValues(t *testing.T) {
+	policy := testLabPolicy(t)
+	cfg := defaultConfig()
+	cfg.NotifyOnComplete = true
+	cfg.TickRateMs = 250
+
+	got := policy.apply(cfg)
+	if got.NotifyOnComplete || got.Font != "Big" || got.Keybindings.PauseKey != "x" {
+		t.Fatalf("expected locked values to win, got %+v", got)
+	}
+	if got.TickRateMs != 250 {
+		t.Fatalf("expected unlocked values to be kept, got %d", got.TickRateMs)
+	}
+}
+
+func TestLockedEntriesAreMarkedAndRefuseChanges(t *testing.T) {
+	payload := testPayload()
+	payload.Fonts = []string{"Big", "Standard"}
+	payload.Config.NotifyOnComplete = true
+	m := newModel(payload)
+	m.applyAdminPolicy(testLabPolicy(t))
+
+	if m.payload.Config.NotifyOnComplete {
+		t.Fatalf("expected the loaded config to comply with the policy")
+	}
+
+	m.setSection(sectionAlerts)
+	m.jumpToMenuEntry("notify")
+	entry := m.menu.SelectedItem().(menuEntry)
+	if !entry.locked || !strings.Contains(entry.Title(), "[locked]") {
+		t.Fatalf("expected lock marker, got %q", entry.Title())
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+	if next.payload.Config.NotifyOnComplete || !strings.Contains(next.notice, "locked") {
+		t.Fatalf("expected toggle to be refused, notice=%q", next.notice)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if !strings.Contains(updated.(model).notice, "locked") {
+		t.Fatalf("expected reset to be refused")
+	}
+}
+
+func TestResetAllKeepsLockedValues(t *testing.T) {
+	payload := testPayload()
+	payload.Fonts = []string{"Big", "Standard"}
+	m := newModel(payload)
+	m.applyAdminPolicy(testLabPolicy(t))
+	m.payload.Config.Keybindings.RestartKey = "z"
+
+	m.resetAll()
+	if m.payload.Config.Font != "Big" || m.payload.Config.Keybindings.PauseKey != "x" {
+		t.Fatalf("expected locked values to survive reset, got %+v", m.payload.Config)
+	}
+	if m.payload.Config.Keybindings.RestartKey != defaultKeybindings.RestartKey {
+		t.Fatalf("expected unlocked key to reset, got %q", m.payload.Config.Keybindings.RestartKey)
+	}
+}
+
+func TestPaletteValueCommandRespectsLock(t *testing.T) {
+	payload := testPayload()
+	payload.Fonts = []string{"Big", "Standard"}
+	m := newModel(payload)
+	m.applyAdminPolicy(testLabPolicy(t))
+
+	m.openPalette()
+	m.paletteList.SetItems(filterPaletteCommands(m.paletteCommands, "System notification: On"))
+	m.paletteList.Select(0)
+	m.runSelectedPaletteCommand()
+	if m.payload.Config.NotifyOnComplete || !strings.Contains(m.notice, "locked") {
+		t.Fatalf("expected palette to refuse locked value, notice=%q", m.notice)
+	}
+}
+
+func TestSaveEnforcesPolicy(t *testing.T) {
+	payload := testPayload()
+	payload.Fonts = []string{"Big", "Standard"}
+	payload.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	m := newModel(payload)
+	m.writePolicy = configWritePolicy{dir: filepath.Dir(payload.ConfigPath)}
+	m.applyAdminPolicy(testLabPolicy(t))
+	m.payload.Config.NotifyOnComplete = true
+
+	if err := m.save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	saved, err := readConfigFile(payload.ConfigPath)
+	if err != nil || saved.NotifyOnComplete {
+		t.Fatalf("expected saved config to honor policy, got %+v err=%v", saved, err)
+	}
+}
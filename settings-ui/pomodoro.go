@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+const (
+	minPomodoroMinutes = 1
+	maxPomodoroMinutes = 180
+	minPomodoroCycles  = 1
+	maxPomodoroCycles  = 12
+)
+
+// pomodoroConfig is the "pomodoro" section of the config: phase lengths in
+// minutes, how many focus phases come before a long break, whether the next
+// phase starts by itself, and the message shown when each phase ends.
+type pomodoroConfig struct {
+	FocusMinutes          int    `json:"focusMinutes"`
+	ShortBreakMinutes     int    `json:"shortBreakMinutes"`
+	LongBreakMinutes      int    `json:"longBreakMinutes"`
+	CyclesBeforeLongBreak int    `json:"cyclesBeforeLongBreak"`
+	AutoStartBreaks       bool   `json:"autoStartBreaks"`
+	AutoStartFocus        bool   `json:"autoStartFocus"`
+	FocusMessage          string `json:"focusMessage"`
+	ShortBreakMessage     string `json:"shortBreakMessage"`
+	LongBreakMessage      string `json:"longBreakMessage"`
+}
+
+var defaultPomodoro = pomodoroConfig{
+	FocusMinutes:          25,
+	ShortBreakMinutes:     5,
+	LongBreakMinutes:      15,
+	CyclesBeforeLongBreak: 4,
+	AutoStartBreaks:       true,
+	AutoStartFocus:        false,
+	FocusMessage:          "Focus done. Time for a break.",
+	ShortBreakMessage:     "Break over. Back to focus.",
+	LongBreakMessage:      "Long break over. Ready for the next set?",
+}
+
+func clampInt(value int, min int, max int, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func normalizePomodoro(cfg pomodoroConfig) pomodoroConfig {
+	result := defaultPomodoro
+	result.FocusMinutes = clampInt(cfg.FocusMinutes, minPomodoroMinutes, maxPomodoroMinutes, result.FocusMinutes)
+	result.ShortBreakMinutes = clampInt(cfg.ShortBreakMinutes, minPomodoroMinutes, maxPomodoroMinutes, result.ShortBreakMinutes)
+	result.LongBreakMinutes = clampInt(cfg.LongBreakMinutes, minPomodoroMinutes, maxPomodoroMinutes, result.LongBreakMinutes)
+	result.CyclesBeforeLongBreak = clampInt(cfg.CyclesBeforeLongBreak, minPomodoroCycles, maxPomodoroCycles, result.CyclesBeforeLongBreak)
+	result.AutoStartBreaks = cfg.AutoStartBreaks
+	result.AutoStartFocus = cfg.AutoStartFocus
+	if cfg.FocusMessage != "" {
+		result.FocusMessage = normalizeCompletionMessage(cfg.FocusMessage)
+	}
+	if cfg.ShortBreakMessage != "" {
+		result.ShortBreakMessage = normalizeCompletionMessage(cfg.ShortBreakMessage)
+	}
+	if cfg.LongBreakMessage != "" {
+		result.LongBreakMessage = normalizeCompletionMessage(cfg.LongBreakMessage)
+	}
+	return result
+}
+
+type pomodoroPhase int
+
+const (
+	phaseFocus pomodoroPhase = iota
+	phaseShortBreak
+	phaseLongBreak
+)
+
+var pomodoroPhaseNames = []string{"Focus", "Short break", "Long break"}
+
+func (p pomodoroConfig) minutes(phase pomodoroPhase) int {
+	switch phase {
+	case phaseShortBreak:
+		return p.ShortBreakMinutes
+	case phaseLongBreak:
+		return p.LongBreakMinutes
+	default:
+		return p.FocusMinutes
+	}
+}
+
+func (p pomodoroConfig) message(phase pomodoroPhase) string {
+	switch phase {
+	case phaseShortBreak:
+		return p.ShortBreakMessage
+	case phaseLongBreak:
+		return p.LongBreakMessage
+	default:
+		return p.FocusMessage
+	}
+}
+
+func (p pomodoroConfig) autoStart(phase pomodoroPhase) bool {
+	if phase == phaseFocus {
+		return p.AutoStartFocus
+	}
+	return p.AutoStartBreaks
+}
+
+// pomodoroModel runs focus and break phases back to back on the Go clock.
+// cycle is the focus phase within the current set, counting from 1; a break
+// keeps the number of the focus phase it follows.
+type pomodoroModel struct {
+	clock    clockModel
+	settings pomodoroConfig
+	phase    pomodoroPhase
+	cycle    int
+	// waiting is set when a phase has ended and the next one does not start
+	// by itself; the pause key starts it.
+	waiting bool
+	// sessions holds a history entry for each phase that has been left.
+	sessions []historyEntry
+}
+
+func newPomodoroModel(payload statePayload) pomodoroModel {
+	p := pomodoroModel{
+		clock:    newClockModel("timer", 0, payload),
+		settings: payload.Config.Pomodoro,
+		cycle:    1,
+	}
+	p.startPhase(phaseFocus)
+	return p
+}
+
+// notifyPhase sends the completion notification titled after the phase.
+func notifyPhase(phase pomodoroPhase) func(cfg config, initialSeconds int) {
+	return func(cfg config, initialSeconds int) {
+		notifyFinished(fmt.Sprintf("%s finished (%s)", pomodoroPhaseNames[phase], formatHms(initialSeconds)), cfg)
+	}
+}
+
+func (p pomodoroModel) nextPhase() pomodoroPhase {
+	switch {
+	case p.phase != phaseFocus:
+		return phaseFocus
+	case p.cycle >= p.settings.CyclesBeforeLongBreak:
+		return phaseLongBreak
+	default:
+		return phaseShortBreak
+	}
+}
+
+func (p *pomodoroModel) startPhase(phase pomodoroPhase) {
+	// Log the phase being left; the first call has none yet.
+	if p.clock.baseSeconds > 0 {
+		p.sessions = append(p.sessions, p.historyEntry())
+		p.clock.session = sessionTracker{started: p.clock.now()}
+	}
+	if phase == phaseFocus && p.phase != phaseFocus {
+		if p.phase == phaseLongBreak {
+			p.cycle = 1
+		} else {
+			p.cycle++
+		}
+	}
+	p.phase = phase
+	p.waiting = false
+	p.clock.baseSeconds = p.settings.minutes(phase) * 60
+	p.clock.cfg.CompletionMessage = p.settings.message(phase)
+	p.clock.notify = notifyPhase(phase)
+	p.clock.title = p.header()
+	p.clock.restart()
+}
+
+// historyEntry logs the current phase as a session labelled with its name.
+func (p pomodoroModel) historyEntry() historyEntry {
+	entry := p.clock.historyEntry(pomodoroPhaseNames[p.phase])
+	entry.Mode = "pomodoro"
+	return entry
+}
+
+// header replaces "Timer" in the clock header, e.g. "Pomodoro | Focus 2/4".
+func (p pomodoroModel) header() string {
+	return fmt.Sprintf("Pomodoro | %s %d/%d", pomodoroPhaseNames[p.phase], p.cycle, p.settings.CyclesBeforeLongBreak)
+}
+
+// finishPhase runs once the clock reaches zero: the next phase either
+// starts right away or waits for the pause key.
+func (p *pomodoroModel) finishPhase() {
+	next := p.nextPhase()
+	if p.settings.autoStart(next) {
+		p.startPhase(next)
+		return
+	}
+	p.waiting = true
+	p.clock.cfg.CompletionMessage = fmt.Sprintf("%s Press %s to start the %s (%d min).",
+		p.settings.message(p.phase), keyTokenLabel(p.clock.cfg.Keybindings.PauseKey),
+		strings.ToLower(pomodoroPhaseNames[next]), p.settings.minutes(next))
+}
+
+func (p pomodoroModel) Init() tea.Cmd {
+	return p.clock.Init()
+}
+
+func (p pomodoroModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && p.waiting {
+		kb := p.clock.cfg.Keybindings
+		switch token := clockKeyToken(key); token {
+		case kb.PauseKey, kb.PauseAltKey:
+			p.startPhase(p.nextPhase())
+			return p, nil
+		case kb.RestartKey:
+			p.startPhase(p.phase)
+			return p, nil
+		}
+	}
+	wasDone := p.clock.done
+	updated, cmd := p.clock.Update(msg)
+	p.clock = updated.(clockModel)
+	if p.clock.done && !wasDone {
+		p.finishPhase()
+	}
+	return p, cmd
+}
+
+func (p pomodoroModel) View() string {
+	return p.clock.View()
+}
+
+// runPomodoroCommand runs `pomodoro` with the phases from the config.
+func runPomodoroCommand(args []string, configPath string, fontDir string, policy configWritePolicy, admin adminPolicy) int {
+	tagValues, args, err := extractFlag(args, "tag")
+	if err == nil && len(args) > 0 {
+		err = fmt.Errorf("unexpected arguments: %s", strings.Join(args, " "))
+	}
+	var tags []string
+	if err == nil {
+		tags, err = normalizeTags(tagValues)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Fprintln(os.Stderr, "Pomodoro requires an interactive terminal (TTY).")
+		return 1
+	}
+	payload, err := loadStandalonePayload(configPath, fontDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	payload.Config = admin.apply(payload.Config)
+
+	p := newPomodoroModel(payload)
+	p.clock.writePolicy = policy
+	p.clock.admin = admin
+	p.clock.tags = tags
+	final, err := tea.NewProgram(p, tea.WithAltScreen()).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Pomodoro failed: %v\n", err)
+		return 1
+	}
+	if final, ok := final.(pomodoroModel); ok {
+		recordSessions(payload, append(final.sessions, final.historyEntry())...)
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testPomodoro(settings pomodoroConfig) (pomodoroModel, *fakeClock) {
+	payload := testPayload()
+	payload.Config.Pomodoro = settings
+	p := newPomodoroModel(payload)
+	return p, newFakeClock(&p.clock)
+}
+
+// finishCurrentPhase lets the current phase run out and delivers a tick.
+func finishCurrentPhase(p pomodoroModel, fake *fakeClock) pomodoroModel {
+	fake.t = fake.t.Add(time.Duration(p.clock.baseSeconds) * time.Second)
+	updated, _ := p.Update(clockTickMsg(fake.t))
+	return updated.(pomodoroModel)
+}
+
+func TestPomodoroLongBreakAfterConfiguredCycles(t *testing.T) {
+	settings := defaultPomodoro
+	settings.CyclesBeforeLongBreak = 2
+	settings.AutoStartFocus = true
+	p, fake := testPomodoro(settings)
+
+	want := []struct {
+		phase pomodoroPhase
+		cycle int
+	}{
+		{phaseShortBreak, 1},
+		{phaseFocus, 2},
+		{phaseLongBreak, 2},
+		{phaseFocus, 1},
+	}
+	for _, step := range want {
+		p = finishCurrentPhase(p, fake)
+		if p.phase != step.phase || p.cycle != step.cycle || p.waiting {
+			t.Fatalf("expected %s %d running, got %s %d (waiting=%v)",
+				pomodoroPhaseNames[step.phase], step.cycle, pomodoroPhaseNames[p.phase], p.cycle, p.waiting)
+		}
+		if got := p.clock.displaySeconds(); got != settings.minutes(step.phase)*60 {
+			t.Fatalf("expected a fresh %s of %d minutes, got %d seconds", pomodoroPhaseNames[step.phase], settings.minutes(step.phase), got)
+		}
+	}
+	if p.clock.title != "Pomodoro | Focus 1/2" {
+		t.Fatalf("unexpected header %q", p.clock.title)
+	}
+}
+
+func TestPomodoroWaitsForPauseKeyWithoutAutoStart(t *testing.T) {
+	settings := defaultPomodoro
+	settings.AutoStartBreaks = false
+	p, fake := testPomodoro(settings)
+
+	p = finishCurrentPhase(p, fake)
+	if !p.waiting || p.phase != phaseFocus || p.clock.state() != frameDone {
+		t.Fatalf("expected the finished focus phase to wait, got %s waiting=%v", pomodoroPhaseNames[p.phase], p.waiting)
+	}
+	if msg := p.clock.cfg.CompletionMessage; !strings.HasPrefix(msg, settings.FocusMessage) || !strings.Contains(msg, "Press p to start the short break") {
+		t.Fatalf("unexpected waiting message %q", msg)
+	}
+
+	p = press(p, runeKey("p"))
+	if p.waiting || p.phase != phaseShortBreak || p.clock.paused {
+		t.Fatalf("expected the pause key to start the short break, got %s paused=%v", pomodoroPhaseNames[p.phase], p.clock.paused)
+	}
+	if p.clock.cfg.CompletionMessage != settings.ShortBreakMessage {
+		t.Fatalf("expected the short break message, got %q", p.clock.cfg.CompletionMessage)
+	}
+}
+
+func TestPomodoroRestartRepeatsFinishedPhase(t *testing.T) {
+	settings := defaultPomodoro
+	settings.AutoStartBreaks = false
+	p, fake := testPomodoro(settings)
+
+	p = finishCurrentPhase(p, fake)
+	p = press(p, runeKey("r"))
+	if p.waiting || p.phase != phaseFocus || p.cycle != 1 || p.clock.displaySeconds() != settings.FocusMinutes*60 {
+		t.Fatalf("expected restart to repeat focus 1, got %s %d", pomodoroPhaseNames[p.phase], p.cycle)
+	}
+}
+
+func TestNormalizePomodoroClampsValues(t *testing.T) {
+	got := normalizePomodoro(pomodoroConfig{FocusMinutes: 500, ShortBreakMinutes: -3, CyclesBeforeLongBreak: 40, AutoStartFocus: true})
+	if got.FocusMinutes != maxPomodoroMinutes || got.ShortBreakMinutes != minPomodoroMinutes {
+		t.Fatalf("expected minutes to be clamped, got %+v", got)
+	}
+	if got.LongBreakMinutes != defaultPomodoro.LongBreakMinutes || got.CyclesBeforeLongBreak != maxPomodoroCycles {
+		t.Fatalf("expected defaults and clamped cycles, got %+v", got)
+	}
+	if got.FocusMessage != defaultPomodoro.FocusMessage || !got.AutoStartFocus {
+		t.Fatalf("expected default messages and kept toggles, got %+v", got)
+	}
+}
+
+func TestNumberEditorClampsStepsAndRejectsOutOfRange(t *testing.T) {
+	payload := testPayload()
+	payload.Config.Pomodoro = defaultPomodoro
+	m := newModel(payload)
+	m.openNumberEditor("pomodoroCycles")
+
+	for i := 0; i < 3; i++ {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+		m = updated.(model)
+	}
+	if m.numberInput.Value() != "12" {
+		t.Fatalf("expected shift+right to clamp at %d, got %q", maxPomodoroCycles, m.numberInput.Value())
+	}
+
+	m.numberInput.SetValue("0")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.err == nil || m.screen != screenNumberEditor {
+		t.Fatalf("expected 0 cycles to be rejected")
+	}
+
+	m.numberInput.SetValue("3")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.screen != screenMain || m.payload.Config.Pomodoro.CyclesBeforeLongBreak != 3 {
+		t.Fatalf("expected 3 cycles to be saved, got %d", m.payload.Config.Pomodoro.CyclesBeforeLongBreak)
+	}
+}
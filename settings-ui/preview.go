@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+)
+
+// previewSeconds is the sample time shown for each preview state.
+var previewSeconds = map[frameState]int{
+	frameRunning: 25 * 60,
+	framePaused:  12*60 + 34,
+	frameDone:    0,
+}
+
+func (m *model) cyclePreviewState(step int) {
+	count := len(frameStateNames)
+	m.previewState = frameState(((int(m.previewState)+step)%count + count) % count)
+}
+
+func (m model) previewView(footer string) string {
+	height := m.height
+	if height <= 0 {
+		height = fallbackFrameHeight
+	}
+	frame := renderFrame(m.fonts, frameSpec{
+		mode:    "timer",
+		seconds: previewSeconds[m.previewState],
+		state:   m.previewState,
+		cfg:     m.payload.Config,
+		width:   m.width,
+		height:  height - 2,
+	})
+	states := ""
+	for idx, name := range frameStateNames {
+		if frameState(idx) == m.previewState {
+			states += activeTabStyle.Render(name)
+		} else {
+			states += inactiveTabStyle.Render(name)
+		}
+	}
+	return fmt.Sprintf("%s\n%s  %s", frame, states, footer)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPreviewFlipsBetweenStates(t *testing.T) {
+	payload := testPayload()
+	payload.Config.CompletionMessage = "Tea is ready"
+	m := newModel(payload)
+	m.jumpToMenuEntry("preview")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+	if next.screen != screenPreview || next.previewState != frameRunning {
+		t.Fatalf("expected running preview, got screen %v state %v", next.screen, next.previewState)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	next = updated.(model)
+	if next.previewState != frameDone {
+		t.Fatalf("expected left to wrap to done, got %v", next.previewState)
+	}
+	if view := next.View(); !strings.Contains(view, "Tea is ready") {
+		t.Fatalf("expected completion message in done preview, got:\n%s", view)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(model).screen != screenMain {
+		t.Fatalf("expected esc to leave the preview")
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// protocolVersion is the newest stdin/stdout protocol this binary speaks.
+// Version 1: request is a state payload plus protocolVersion; the result is
+// a single JSON object on stdout.
+const protocolVersion = 1
+
+// protocolCapabilities advertises optional behaviour so wrappers can adapt
+// without bumping protocolVersion.
+var protocolCapabilities = []string{"changedFields", "fontDir", "stateFd"}
+
+const (
+	protocolStatusSaved     = "saved"
+	protocolStatusCancelled = "cancelled"
+	protocolStatusError     = "error"
+)
+
+type protocolRequest struct {
+	ProtocolVersion int `json:"protocolVersion"`
+	statePayload
+}
+
+type protocolResult struct {
+	ProtocolVersion int      `json:"protocolVersion"`
+	Capabilities    []string `json:"capabilities"`
+	Status          string   `json:"status"`
+	Config          *config  `json:"config,omitempty"`
+	Changed         []string `json:"changed"`
+	Error           string   `json:"error,omitempty"`
+}
+
+func negotiateProtocolVersion(requested int) (int, error) {
+	if requested <= 0 {
+		return 0, errors.New("protocolVersion is missing in request")
+	}
+	if requested > protocolVersion {
+		return protocolVersion, nil
+	}
+	return requested, nil
+}
+
+func readProtocolRequest(r io.Reader) (protocolRequest, statePayload, error) {
+	var request protocolRequest
+	if err := json.NewDecoder(r).Decode(&request); err != nil {
+		return protocolRequest{}, statePayload{}, fmt.Errorf("invalid request: %w", err)
+	}
+	version, err := negotiateProtocolVersion(request.ProtocolVersion)
+	if err != nil {
+		return protocolRequest{}, statePayload{}, err
+	}
+	request.ProtocolVersion = version
+	payload := request.statePayload
+	if payload.ConfigPath == "" {
+		return request, statePayload{}, errors.New("configPath is missing in request")
+	}
+	if payload.FontDir == "" {
+		payload.FontDir = findFontDir(fontDirCandidates())
+	}
+	return request, finishPayload(payload), nil
+}
+
+func flattenJSON(prefix string, value interface{}, out map[string]interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		out[prefix] = value
+		return
+	}
+	for k, v := range object {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+		flattenJSON(name, v, out)
+	}
+}
+
+// configPaths flattens cfg into its dotted JSON paths and leaf values.
+func configPaths(cfg config) map[string]interface{} {
+	var generic interface{}
+	text, _ := json.Marshal(cfg)
+	_ = json.Unmarshal(text, &generic)
+	out := map[string]interface{}{}
+	flattenJSON("", generic, out)
+	return out
+}
+
+// changedFields lists the dotted JSON paths (e.g. "keybindings.pauseKey")
+// whose values differ between two configs.
+func changedFields(before config, after config) []string {
+	a, b := configPaths(before), configPaths(after)
+	changed := []string{}
+	for name, value := range b {
+		if !reflect.DeepEqual(a[name], value) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func protocolResultFor(version int, initial config, m model, runErr error) protocolResult {
+	result := protocolResult{
+		ProtocolVersion: version,
+		Capabilities:    protocolCapabilities,
+		Changed:         []string{},
+	}
+	switch {
+	case runErr != nil:
+		result.Status = protocolStatusError
+		result.Error = runErr.Error()
+	case m.crash.crashed():
+		result.Status = protocolStatusError
+		result.Error = fmt.Sprintf("settings UI crashed: %v", m.crash.err)
+	case m.err != nil:
+		result.Status = protocolStatusError
+		result.Error = m.err.Error()
+	case m.cancelled:
+		result.Status = protocolStatusCancelled
+	default:
+		cfg := m.payload.Config
+		result.Status = protocolStatusSaved
+		result.Config = &cfg
+		result.Changed = changedFields(initial, cfg)
+	}
+	return result
+}
+
+func openTTYOutput() (*os.File, error) {
+	if runtime.GOOS == "windows" {
+		return os.OpenFile("CONOUT$", os.O_RDWR, 0)
+	}
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
+
+func writeProtocolResult(w io.Writer, result protocolResult) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(result)
+}
+
+// runProtocol serves one settings session over the stdin/stdout protocol.
+// The request comes from stdin or stateFD, the UI talks to the terminal
+// directly, and exactly one JSON result is written to stdout. The exit code
+// matches the state file mode: 0 saved, 2 cancelled, 1 error.
+func runProtocol(stateFD int, policy configWritePolicy, admin adminPolicy) int {
+	input := io.Reader(os.Stdin)
+	if stateFD >= 0 {
+		file := os.NewFile(uintptr(stateFD), "state-fd")
+		if file == nil {
+			writeProtocolResult(os.Stdout, protocolResult{
+				ProtocolVersion: protocolVersion,
+				Capabilities:    protocolCapabilities,
+				Status:          protocolStatusError,
+				Changed:         []string{},
+				Error:           fmt.Sprintf("invalid --state-fd %d", stateFD),
+			})
+			return 1
+		}
+		defer file.Close()
+		input = file
+	}
+
+	request, payload, err := readProtocolRequest(input)
+	if err != nil {
+		writeProtocolResult(os.Stdout, protocolResult{
+			ProtocolVersion: protocolVersion,
+			Capabilities:    protocolCapabilities,
+			Status:          protocolStatusError,
+			Changed:         []string{},
+			Error:           err.Error(),
+		})
+		return 1
+	}
+
+	tty, err := openTTYOutput()
+	if err != nil {
+		writeProtocolResult(os.Stdout, protocolResultFor(request.ProtocolVersion, payload.Config, model{}, fmt.Errorf("cannot open terminal: %w", err)))
+		return 1
+	}
+	defer tty.Close()
+
+	initial := newModel(payload)
+	initial.writePolicy = policy
+	initial.applyAdminPolicy(admin)
+	initial.offerDraftRestore()
+	p := tea.NewProgram(initial, tea.WithAltScreen(), tea.WithInputTTY(), tea.WithOutput(tty))
+	finalModel, runErr := p.Run()
+	m, ok := finalModel.(model)
+	if !ok && runErr == nil {
+		// Bubble Tea returns no model when it had to catch a panic itself.
+		runErr = errors.New("settings UI exited unexpectedly")
+	}
+	result := protocolResultFor(request.ProtocolVersion, payload.Config, m, runErr)
+	writeProtocolResult(os.Stdout, result)
+
+	switch result.Status {
+	case protocolStatusSaved:
+		return 0
+	case protocolStatusCancelled:
+		return 2
+	default:
+		return 1
+	}
+}
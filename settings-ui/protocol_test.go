@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadProtocolRequestNegotiatesVersion(t *testing.T) {
+	request, payload, err := readProtocolRequest(strings.NewReader(
+		`{"protocolVersion": 7, "configPath": "/tmp/config.json", "config": {"font": "Big"}, "fonts": ["Standard", "Big"]}`,
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.ProtocolVersion != protocolVersion {
+		t.Fatalf("expected newer request to negotiate down to %d, got %d", protocolVersion, request.ProtocolVersion)
+	}
+	if payload.ConfigPath != "/tmp/config.json" || payload.Config.Font != "Big" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	if _, _, err := readProtocolRequest(strings.NewReader(`{"configPath": "/tmp/config.json"}`)); err == nil {
+		t.Fatalf("expected missing protocolVersion to be rejected")
+	}
+}
+
+func TestChangedFieldsUsesDottedJSONPaths(t *testing.T) {
+	before := testPayload().Config
+	after := before
+	after.Font = "Big"
+	after.Keybindings.PauseKey = "x"
+	after.SettingsKeys.Save = []string{"ctrl+o"}
+
+	got := changedFields(before, after)
+	want := []string{"font", "keybindings.pauseKey", "settingsKeys.save"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestProtocolResultStatuses(t *testing.T) {
+	m := newModel(testPayload())
+	initial := m.payload.Config
+
+	m.payload.Config.ShowHeader = false
+	saved := protocolResultFor(protocolVersion, initial, m, nil)
+	if saved.Status != protocolStatusSaved || saved.Config == nil || !reflect.DeepEqual(saved.Changed, []string{"showHeader"}) {
+		t.Fatalf("unexpected saved result %+v", saved)
+	}
+
+	m.cancelled = true
+	if got := protocolResultFor(protocolVersion, initial, m, nil); got.Status != protocolStatusCancelled || got.Config != nil {
+		t.Fatalf("unexpected cancelled result %+v", got)
+	}
+
+	if got := protocolResultFor(protocolVersion, initial, m, errors.New("boom")); got.Status != protocolStatusError || got.Error != "boom" {
+		t.Fatalf("unexpected error result %+v", got)
+	}
+}
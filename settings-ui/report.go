@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	reportDateLayout = "2006-01-02"
+	untaggedLabel    = "(untagged)"
+)
+
+// tagTotal is the time logged under one tag. Tag is empty for sessions
+// without tags; the reports write it as untaggedLabel.
+type tagTotal struct {
+	Tag      string  `json:"tag"`
+	Sessions int     `json:"sessions"`
+	Seconds  int     `json:"seconds"`
+	Hours    float64 `json:"hours"`
+}
+
+// tagReport sums the history per tag over the days from From to To,
+// both included. A session with several tags counts toward each of them,
+// so Total, which counts every session once, can be less than the sum.
+type tagReport struct {
+	From  time.Time
+	To    time.Time
+	Tags  []tagTotal
+	Total tagTotal
+}
+
+func reportHours(seconds int) float64 {
+	return math.Round(float64(seconds)/36) / 100
+}
+
+// parseReportDate reads a --from or --to day in now's zone; today and
+// yesterday are accepted as well.
+func parseReportDate(text string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "today":
+		return startOfDay(now), nil
+	case "yesterday":
+		return startOfDay(now).AddDate(0, 0, -1), nil
+	}
+	day, err := time.ParseInLocation(reportDateLayout, strings.TrimSpace(text), now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, today or yesterday)", text)
+	}
+	return day, nil
+}
+
+// buildTagReport groups the sessions that started between from and to by
+// tag. With filter set, only sessions carrying one of those tags count and
+// only those tags are listed.
+func buildTagReport(entries []historyEntry, from, to time.Time, filter []string) tagReport {
+	report := tagReport{From: from, To: to}
+	end := to.AddDate(0, 0, 1)
+	totals := map[string]*tagTotal{}
+	for _, entry := range entries {
+		start := entry.Start.In(from.Location())
+		if start.Before(from) || !start.Before(end) {
+			continue
+		}
+		tags := entry.Tags
+		if len(filter) > 0 {
+			tags = nil
+			for _, tag := range entry.Tags {
+				if containsFold(filter, tag) {
+					tags = append(tags, tag)
+				}
+			}
+			if len(tags) == 0 {
+				continue
+			}
+		}
+		if len(tags) == 0 {
+			tags = []string{""}
+		}
+		for _, tag := range tags {
+			key := strings.ToLower(tag)
+			if totals[key] == nil {
+				totals[key] = &tagTotal{Tag: tag}
+			}
+			totals[key].Sessions++
+			totals[key].Seconds += entry.ActualSeconds
+		}
+		report.Total.Sessions++
+		report.Total.Seconds += entry.ActualSeconds
+	}
+
+	for _, total := range totals {
+		total.Hours = reportHours(total.Seconds)
+		report.Tags = append(report.Tags, *total)
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		a, b := report.Tags[i], report.Tags[j]
+		if a.Seconds != b.Seconds {
+			return a.Seconds > b.Seconds
+		}
+		return strings.ToLower(a.Tag) < strings.ToLower(b.Tag)
+	})
+	report.Total.Hours = reportHours(report.Total.Seconds)
+	return report
+}
+
+// reportTagName is how a tag is written in every report format; sessions
+// without tags are listed as untaggedLabel.
+func reportTagName(tag string) string {
+	if tag == "" {
+		return untaggedLabel
+	}
+	return tag
+}
+
+func writeReportTable(w io.Writer, report tagReport) error {
+	fmt.Fprintf(w, "Time per tag, %s to %s\n\n", report.From.Format("Mon 2 Jan 2006"), report.To.Format("Mon 2 Jan 2006"))
+	if report.Total.Sessions == 0 {
+		_, err := fmt.Fprintln(w, "No sessions in this range.")
+		return err
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(table, "TAG\tSESSIONS\tTIME\tHOURS")
+	rows := append(append([]tagTotal{}, report.Tags...), report.Total)
+	for idx, row := range rows {
+		name := reportTagName(row.Tag)
+		if idx == len(rows)-1 {
+			name = "Total"
+		}
+		fmt.Fprintf(table, "%s\t%d\t%s\t%.2f\n", name, row.Sessions, formatStatsDuration(row.Seconds), row.Hours)
+	}
+	return table.Flush()
+}
+
+func writeReportCSV(w io.Writer, report tagReport) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"tag", "from", "to", "sessions", "seconds", "hours"}); err != nil {
+		return err
+	}
+	// The last row is the total, as in the table.
+	rows := append(append([]tagTotal{}, report.Tags...), report.Total)
+	for idx, row := range rows {
+		name := reportTagName(row.Tag)
+		if idx == len(rows)-1 {
+			name = "Total"
+		}
+		if err := out.Write([]string{
+			name,
+			report.From.Format(reportDateLayout),
+			report.To.Format(reportDateLayout),
+			strconv.Itoa(row.Sessions),
+			strconv.Itoa(row.Seconds),
+			strconv.FormatFloat(row.Hours, 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func writeReportJSON(w io.Writer, report tagReport) error {
+	tags := make([]tagTotal, 0, len(report.Tags))
+	for _, row := range report.Tags {
+		row.Tag = reportTagName(row.Tag)
+		tags = append(tags, row)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{
+		"from": report.From.Format(reportDateLayout),
+		"to":   report.To.Format(reportDateLayout),
+		"tags": tags,
+		"total": map[string]interface{}{
+			"sessions": report.Total.Sessions,
+			"seconds":  report.Total.Seconds,
+			"hours":    report.Total.Hours,
+		},
+	})
+}
+
+// exportReport writes the report to path, or stdout for "-". An empty
+// format is taken from the file extension: a table on stdout, CSV for
+// other files.
+func exportReport(path string, format string, report tagReport) error {
+	if format == "" {
+		switch {
+		case strings.EqualFold(filepath.Ext(path), ".json"):
+			format = "json"
+		case path == "-" || strings.EqualFold(filepath.Ext(path), ".txt"):
+			format = "table"
+		default:
+			format = "csv"
+		}
+	}
+	var write func(io.Writer, tagReport) error
+	switch strings.ToLower(format) {
+	case "table":
+		write = writeReportTable
+	case "csv":
+		write = writeReportCSV
+	case "json":
+		write = writeReportJSON
+	default:
+		return fmt.Errorf("unknown report format %q (use table, csv or json)", format)
+	}
+	if path == "-" {
+		return write(os.Stdout, report)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := write(file, report); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// runReportCommand runs `report`, summing the history per tag. The range
+// defaults to the current week, Monday to today.
+func runReportCommand(args []string, configPath string, fontDir string) int {
+	tagValues, args, err := extractFlag(args, "tag")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	filter, err := normalizeTags(tagValues)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	fromText := flags.String("from", "", "First day, YYYY-MM-DD (default: Monday of this week)")
+	toText := flags.String("to", "today", "Last day, YYYY-MM-DD (default: today)")
+	format := flags.String("format", "", "table, csv or json (default: from the --output extension)")
+	output := flags.String("output", "-", "Write the report to this file (- for stdout)")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n\n%s\n", strings.Join(flags.Args(), " "), commandUsage)
+		return 1
+	}
+
+	now := time.Now()
+	from := startOfWeek(now)
+	if *fromText != "" {
+		if from, err = parseReportDate(*fromText, now); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	to, err := parseReportDate(*toText, now)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if to.Before(from) {
+		fmt.Fprintf(os.Stderr, "--to %s is before --from %s\n", to.Format(reportDateLayout), from.Format(reportDateLayout))
+		return 1
+	}
+
+	payload, err := loadStandalonePayload(configPath, fontDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	entries, err := readHistory(historyPath(payload.ConfigPath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read history: %v\n", err)
+		return 1
+	}
+	if err := exportReport(*output, *format, buildTagReport(entries, from, to, filter)); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractSessionTags(t *testing.T) {
+	session, rest, err := extractSessionTags([]string{"45", "min", "--label", "code  review", "--tag", "work,client", "--tag=Work", "--tag", "review"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.label != "code review" || strings.Join(session.tags, "|") != "work|client|review" {
+		t.Fatalf("unexpected session tags %+v", session)
+	}
+	if strings.Join(rest, " ") != "45 min" {
+		t.Fatalf("expected the duration to be left over, got %q", rest)
+	}
+	if _, _, err := extractSessionTags([]string{"5", "min", "--tag", strings.Repeat("x", maxSessionTagLen+1)}); err == nil {
+		t.Fatalf("expected an overlong tag to be rejected")
+	}
+}
+
+func taggedEntry(start time.Time, seconds int, tags ...string) historyEntry {
+	entry := statsEntry(start, seconds)
+	entry.Tags = tags
+	return entry
+}
+
+func TestBuildTagReportSumsPerTagInRange(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2026, 3, d, h, 0, 0, 0, time.UTC) }
+	entries := []historyEntry{
+		taggedEntry(day(8, 23), 3600, "work"),
+		taggedEntry(day(9, 9), 2700, "work", "review"),
+		taggedEntry(day(10, 9), 1800, "Work"),
+		taggedEntry(day(11, 9), 600),
+		taggedEntry(day(15, 23), 900, "music"),
+		taggedEntry(day(16, 0), 3600, "work"),
+	}
+	from, to := day(9, 0), day(15, 0)
+
+	report := buildTagReport(entries, from, to, nil)
+	want := []tagTotal{
+		{Tag: "work", Sessions: 2, Seconds: 4500, Hours: 1.25},
+		{Tag: "review", Sessions: 1, Seconds: 2700, Hours: 0.75},
+		{Tag: "music", Sessions: 1, Seconds: 900, Hours: 0.25},
+		{Tag: "", Sessions: 1, Seconds: 600, Hours: 0.17},
+	}
+	if len(report.Tags) != len(want) {
+		t.Fatalf("expected %d tags, got %+v", len(want), report.Tags)
+	}
+	for idx, row := range want {
+		if report.Tags[idx] != row {
+			t.Fatalf("row %d: expected %+v, got %+v", idx, row, report.Tags[idx])
+		}
+	}
+	if report.Total.Sessions != 4 || report.Total.Seconds != 6000 {
+		t.Fatalf("expected each session counted once in the total, got %+v", report.Total)
+	}
+
+	filtered := buildTagReport(entries, from, to, []string{"REVIEW"})
+	if len(filtered.Tags) != 1 || filtered.Tags[0].Tag != "review" || filtered.Total.Seconds != 2700 {
+		t.Fatalf("expected only the review session, got %+v", filtered)
+	}
+}
+
+func TestReportExportFormats(t *testing.T) {
+	from := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	report := buildTagReport([]historyEntry{
+		taggedEntry(from.Add(9*time.Hour), 5400, "work"),
+		taggedEntry(from.Add(12*time.Hour), 1800),
+	}, from, from.AddDate(0, 0, 6), nil)
+
+	var csvOut bytes.Buffer
+	if err := writeReportCSV(&csvOut, report); err != nil {
+		t.Fatal(err)
+	}
+	if csvOut.String() != "tag,from,to,sessions,seconds,hours\n"+
+		"work,2026-03-09,2026-03-15,1,5400,1.50\n"+
+		"(untagged),2026-03-09,2026-03-15,1,1800,0.50\n"+
+		"Total,2026-03-09,2026-03-15,2,7200,2.00\n" {
+		t.Fatalf("unexpected CSV:\n%s", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeReportJSON(&jsonOut, report); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		From  string     `json:"from"`
+		Tags  []tagTotal `json:"tags"`
+		Total struct {
+			Hours float64 `json:"hours"`
+		} `json:"total"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.From != "2026-03-09" || len(decoded.Tags) != 2 || decoded.Tags[1].Tag != untaggedLabel || decoded.Total.Hours != 2 {
+		t.Fatalf("unexpected JSON report %+v", decoded)
+	}
+
+	var table bytes.Buffer
+	if err := writeReportTable(&table, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "Mon 9 Mar 2026 to Sun 15 Mar 2026") || !strings.Contains(table.String(), "1h 30m") || !strings.Contains(table.String(), untaggedLabel) {
+		t.Fatalf("unexpected table:\n%s", table.String())
+	}
+}
+
+func TestParseReportDate(t *testing.T) {
+	now := time.Date(2026, 3, 11, 15, 4, 0, 0, time.UTC)
+	if got, err := parseReportDate("yesterday", now); err != nil || !got.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected yesterday %v, %v", got, err)
+	}
+	if _, err := parseReportDate("11/03/2026", now); err == nil {
+		t.Fatalf("expected a non-ISO date to be rejected")
+	}
+}
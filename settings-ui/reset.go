@@ -0,0 +1,73 @@
+package main
+
+// resetSetting restores the default for a single menu entry and reports
+// whether the entry holds a resettable value. Locked entries are left alone.
+func (m *model) resetSetting(id string) bool {
+	if m.admin.menuEntryLocked(id) {
+		return false
+	}
+	defaults := defaultConfig()
+	cfg := &m.payload.Config
+	switch id {
+	case "font":
+		cfg.Font = defaults.Font
+		if !containsString(m.payload.Fonts, cfg.Font) && len(m.payload.Fonts) > 0 {
+			cfg.Font = m.payload.Fonts[0]
+		}
+	case "randomFavorites":
+		cfg.RandomFromFavorites = defaults.RandomFromFavorites
+	case "center":
+		cfg.CenterDisplay = defaults.CenterDisplay
+	case "header":
+		cfg.ShowHeader = defaults.ShowHeader
+	case "controls":
+		cfg.ShowControls = defaults.ShowControls
+	case "tickRate":
+		cfg.TickRateMs = defaults.TickRateMs
+	case "dayFormat":
+		cfg.DayFormat = defaults.DayFormat
+	case "timeZone":
+		cfg.TimeZone = defaults.TimeZone
+	case "planDir":
+		cfg.PlanDir = defaults.PlanDir
+	case "presets":
+		cfg.Presets = defaults.Presets
+	case "message":
+		cfg.CompletionMessage = defaults.CompletionMessage
+	case "notify":
+		cfg.NotifyOnComplete = defaults.NotifyOnComplete
+	case "sound":
+		cfg.PlaySoundOnComplete = defaults.PlaySoundOnComplete
+	case "keymapPreset":
+		cfg.Keybindings = defaults.Keybindings
+	case "pomodoroFocus", "pomodoroShortBreak", "pomodoroLongBreak", "pomodoroCycles", "historyRetention":
+		field := numberFields[id].field
+		*field(cfg) = *field(&defaults)
+	case "pomodoroFocusMessage", "pomodoroShortMessage", "pomodoroLongMessage":
+		field := messageFields[id].field
+		*field(cfg) = *field(&defaults)
+	case "pomodoroAutoBreaks":
+		cfg.Pomodoro.AutoStartBreaks = defaults.Pomodoro.AutoStartBreaks
+	case "pomodoroAutoFocus":
+		cfg.Pomodoro.AutoStartFocus = defaults.Pomodoro.AutoStartFocus
+	case "pauseKey", "pauseAltKey", "restartKey", "styleKey", "exitKey", "exitAltKey", "lapKey", "deleteKey":
+		if err := m.setKeyTokenForTarget(id, keyTokenForTarget(defaults.Keybindings, id)); err != nil {
+			m.err = err
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// resetAll restores every setting editable in the UI. The settings UI's own
+// keys are left alone: they are not shown in the menu and changing them
+// mid-session would be surprising.
+func (m *model) resetAll() {
+	for _, entry := range baseMenuEntries(m.payload.Config, m.presets) {
+		m.resetSetting(entry.id)
+	}
+	m.err = nil
+	m.refreshMenu()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMenuMarksValuesThatDifferFromDefault(t *testing.T) {
+	cfg := testPayload().Config
+	cfg.Font = "Big"
+	for _, item := range buildMenuItems(cfg, builtinKeymapPresets) {
+		entry := item.(menuEntry)
+		switch entry.id {
+		case "font":
+			if !entry.modified || entry.description != "Big (default: Standard)" {
+				t.Fatalf("expected modified font entry, got %+v", entry)
+			}
+		case "center":
+			if entry.modified {
+				t.Fatalf("expected default center entry to be unmarked, got %+v", entry)
+			}
+		}
+	}
+}
+
+func TestResetKeyRestoresSelectedSetting(t *testing.T) {
+	payload := testPayload()
+	payload.Config.Keybindings.RestartKey = "z"
+	m := newModel(payload)
+	m.jumpToMenuEntry("restartKey")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	next := updated.(model)
+
+	if next.payload.Config.Keybindings.RestartKey != defaultKeybindings.RestartKey {
+		t.Fatalf("expected restart key reset, got %q", next.payload.Config.Keybindings.RestartKey)
+	}
+	if next.payload.Config.Keybindings.PauseKey != payload.Config.Keybindings.PauseKey {
+		t.Fatalf("expected other keys untouched")
+	}
+}
+
+func TestResetAllRequiresConfirmation(t *testing.T) {
+	payload := testPayload()
+	payload.Config.Font = "Big"
+	payload.Config.TickRateMs = 500
+	m := newModel(payload)
+	m.jumpToMenuEntry("resetAll")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+	if next.screen != screenConfirmReset || !strings.Contains(next.View(), "Reset all settings") {
+		t.Fatalf("expected confirmation screen, got %v", next.screen)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if cfg := updated.(model).payload.Config; cfg.Font != "Big" || cfg.TickRateMs != 500 {
+		t.Fatalf("expected n to keep settings, got %+v", cfg)
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	cfg := updated.(model).payload.Config
+	if cfg.Font != defaultFont || cfg.TickRateMs != defaultTickRateMs || cfg.NotifyOnComplete != true {
+		t.Fatalf("expected defaults after confirming, got %+v", cfg)
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// statsPeriod picks the table under the heatmap on the statistics screen.
+type statsPeriod int
+
+const (
+	statsDaily statsPeriod = iota
+	statsWeekly
+	statsMonthly
+)
+
+var statsPeriodNames = []string{"Daily", "Weekly", "Monthly"}
+
+const (
+	statsBarWidth     = 30
+	heatmapLabelWidth = 4
+	minHeatmapWeeks   = 4
+	maxHeatmapWeeks   = 53
+)
+
+// heatmapLevels shade a day from nothing recorded to the busiest day shown.
+var heatmapLevels = []string{"·", "░", "▒", "▓", "█"}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek is the Monday that begins t's week.
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// statsTotal is the clock time and session count of a stretch of days.
+type statsTotal struct {
+	seconds  int
+	sessions int
+}
+
+// dailyTotals adds up sessions by the local day they started on.
+func dailyTotals(entries []historyEntry, loc *time.Location) map[time.Time]statsTotal {
+	totals := map[time.Time]statsTotal{}
+	for _, entry := range entries {
+		day := startOfDay(entry.Start.In(loc))
+		total := totals[day]
+		total.seconds += entry.ActualSeconds
+		total.sessions++
+		totals[day] = total
+	}
+	return totals
+}
+
+// totalBetween sums the days in [from, to).
+func totalBetween(daily map[time.Time]statsTotal, from, to time.Time) statsTotal {
+	var sum statsTotal
+	for day, total := range daily {
+		if !day.Before(from) && day.Before(to) {
+			sum.seconds += total.seconds
+			sum.sessions += total.sessions
+		}
+	}
+	return sum
+}
+
+// streaks counts days in a row with recorded time. The current streak
+// still counts yesterday's run while today has nothing yet, since the day
+// is not over.
+func streaks(daily map[time.Time]statsTotal, today time.Time) (current int, longest int) {
+	var days []time.Time
+	for day, total := range daily {
+		if total.seconds > 0 {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	run := 0
+	for idx, day := range days {
+		if idx > 0 && days[idx-1].AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	day := startOfDay(today)
+	if daily[day].seconds == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for daily[day].seconds > 0 {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+// formatStatsDuration shows a total as hours and minutes, e.g. 2h 05m.
+func formatStatsDuration(seconds int) string {
+	minutes := seconds / 60
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+func pluralize(count int, word string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, word)
+	}
+	return fmt.Sprintf("%d %ss", count, word)
+}
+
+func heatmapLevel(seconds, busiest int) string {
+	if seconds <= 0 || busiest <= 0 {
+		return heatmapLevels[0]
+	}
+	steps := len(heatmapLevels) - 1
+	level := (seconds*steps + busiest - 1) / busiest
+	if level > steps {
+		level = steps
+	}
+	return heatmapLevels[level]
+}
+
+// heatmapLines draws one row per weekday and one column per week, ending
+// with the current week, as many weeks as fit in width. Days still ahead
+// this week are left blank.
+func heatmapLines(daily map[time.Time]statsTotal, today time.Time, width int) []string {
+	weeks := (width - heatmapLabelWidth) / 2
+	if weeks < minHeatmapWeeks {
+		weeks = minHeatmapWeeks
+	}
+	if weeks > maxHeatmapWeeks {
+		weeks = maxHeatmapWeeks
+	}
+	today = startOfDay(today)
+	first := startOfWeek(today).AddDate(0, 0, -7*(weeks-1))
+
+	busiest := 0
+	for day, total := range daily {
+		if !day.Before(first) && !day.After(today) && total.seconds > busiest {
+			busiest = total.seconds
+		}
+	}
+
+	months := []rune(strings.Repeat(" ", heatmapLabelWidth+2*weeks))
+	free := heatmapLabelWidth
+	for week := 0; week < weeks; week++ {
+		monday := first.AddDate(0, 0, 7*week)
+		sunday := monday.AddDate(0, 0, 6)
+		if week > 0 && monday.Month() == sunday.Month() && monday.Day() != 1 {
+			continue
+		}
+		column := heatmapLabelWidth + 2*week
+		label := sunday.Format("Jan")
+		if column < free || column+len(label) > len(months) {
+			continue
+		}
+		copy(months[column:], []rune(label))
+		free = column + len(label) + 1
+	}
+
+	lines := []string{strings.TrimRight(string(months), " ")}
+	dayLabels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	for weekday := 0; weekday < 7; weekday++ {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%-*s", heatmapLabelWidth, dayLabels[weekday])
+		for week := 0; week < weeks; week++ {
+			day := first.AddDate(0, 0, 7*week+weekday)
+			if day.After(today) {
+				break
+			}
+			if week > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(heatmapLevel(daily[day].seconds, busiest))
+		}
+		lines = append(lines, b.String())
+	}
+	lines = append(lines, strings.Repeat(" ", heatmapLabelWidth)+"Less "+strings.Join(heatmapLevels, " ")+" More")
+	return lines
+}
+
+// statsRow is one line of the period table, newest last.
+type statsRow struct {
+	label string
+	total statsTotal
+}
+
+func periodRows(daily map[time.Time]statsTotal, today time.Time, period statsPeriod) []statsRow {
+	var rows []statsRow
+	switch period {
+	case statsWeekly:
+		week := startOfWeek(today)
+		for idx := 7; idx >= 0; idx-- {
+			from := week.AddDate(0, 0, -7*idx)
+			rows = append(rows, statsRow{"Week of " + from.Format("2 Jan"), totalBetween(daily, from, from.AddDate(0, 0, 7))})
+		}
+	case statsMonthly:
+		month := startOfMonth(today)
+		for idx := 5; idx >= 0; idx-- {
+			from := month.AddDate(0, -idx, 0)
+			rows = append(rows, statsRow{from.Format("Jan 2006"), totalBetween(daily, from, from.AddDate(0, 1, 0))})
+		}
+	default:
+		day := startOfDay(today)
+		for idx := 6; idx >= 0; idx-- {
+			from := day.AddDate(0, 0, -idx)
+			rows = append(rows, statsRow{from.Format("Mon 2 Jan"), totalBetween(daily, from, from.AddDate(0, 0, 1))})
+		}
+	}
+	return rows
+}
+
+func periodTableLines(rows []statsRow) []string {
+	busiest := 0
+	labelWidth := 0
+	for _, row := range rows {
+		if row.total.seconds > busiest {
+			busiest = row.total.seconds
+		}
+		if len(row.label) > labelWidth {
+			labelWidth = len(row.label)
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		filled := 0
+		if busiest > 0 {
+			filled = row.total.seconds * statsBarWidth / busiest
+		}
+		if filled == 0 && row.total.seconds > 0 {
+			filled = 1
+		}
+		bar := strings.Repeat("█", filled) + strings.Repeat("·", statsBarWidth-filled)
+		lines = append(lines, fmt.Sprintf("%-*s  %s  %7s  %s",
+			labelWidth, row.label, bar, formatStatsDuration(row.total.seconds), pluralize(row.total.sessions, "session")))
+	}
+	return lines
+}
+
+// statsReport renders the whole statistics screen body for the given moment.
+func statsReport(entries []historyEntry, now time.Time, width int, period statsPeriod) string {
+	if len(entries) == 0 {
+		return "No sessions recorded yet. Timers, stopwatches, Pomodoro phases and plans are logged when they end."
+	}
+	daily := dailyTotals(entries, now.Location())
+	today := startOfDay(now)
+	summary := []struct {
+		label string
+		total statsTotal
+	}{
+		{"Today", daily[today]},
+		{"This week", totalBetween(daily, startOfWeek(now), today.AddDate(0, 0, 1))},
+		{"This month", totalBetween(daily, startOfMonth(now), today.AddDate(0, 0, 1))},
+	}
+
+	var b strings.Builder
+	for _, line := range summary {
+		fmt.Fprintf(&b, "%-11s %8s  (%s)\n", line.label, formatStatsDuration(line.total.seconds), pluralize(line.total.sessions, "session"))
+	}
+	current, longest := streaks(daily, now)
+	fmt.Fprintf(&b, "%-11s %8s  (longest %s)\n\n", "Streak", pluralize(current, "day"), pluralize(longest, "day"))
+
+	b.WriteString(strings.Join(heatmapLines(daily, now, width), "\n"))
+	fmt.Fprintf(&b, "\n\n%s\n", statsPeriodTabs(period))
+	b.WriteString(strings.Join(periodTableLines(periodRows(daily, now, period)), "\n"))
+	return b.String()
+}
+
+func statsPeriodTabs(period statsPeriod) string {
+	tabs := make([]string, 0, len(statsPeriodNames))
+	for idx, name := range statsPeriodNames {
+		if statsPeriod(idx) == period {
+			tabs = append(tabs, activeTabStyle.Render(name))
+		} else {
+			tabs = append(tabs, inactiveTabStyle.Render(name))
+		}
+	}
+	return strings.Join(tabs, " ")
+}
+
+func (m *model) openStats() {
+	entries, err := readHistory(historyPath(m.payload.ConfigPath))
+	m.history = entries
+	m.err = err
+	m.screen = screenStats
+}
+
+func (m *model) cycleStatsPeriod(step int) {
+	count := len(statsPeriodNames)
+	m.statsPeriod = statsPeriod(((int(m.statsPeriod)+step)%count + count) % count)
+}
+
+func (m model) statsView(errorLine string, footer string) string {
+	width := m.width
+	if width <= 0 {
+		width = 80
+	}
+	return fmt.Sprintf("Session statistics\n\n%s\n%s\n%s", statsReport(m.history, time.Now(), width, m.statsPeriod), errorLine, footer)
+}
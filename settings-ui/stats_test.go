@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func statsEntry(start time.Time, seconds int) historyEntry {
+	return historyEntry{Mode: "timer", Start: start, End: start.Add(time.Duration(seconds) * time.Second), ActualSeconds: seconds, Completed: true}
+}
+
+func TestStreaksCountConsecutiveDays(t *testing.T) {
+	// Wednesday 11 March 2026, nothing recorded yet today.
+	now := time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)
+	var entries []historyEntry
+	for _, daysAgo := range []int{1, 2, 3, 10, 11, 12, 13, 14} {
+		entries = append(entries, statsEntry(now.AddDate(0, 0, -daysAgo), 600))
+	}
+	daily := dailyTotals(entries, time.UTC)
+
+	current, longest := streaks(daily, now)
+	if current != 3 || longest != 5 {
+		t.Fatalf("expected a current streak of 3 and longest of 5, got %d and %d", current, longest)
+	}
+
+	current, _ = streaks(daily, now.AddDate(0, 0, 1))
+	if current != 0 {
+		t.Fatalf("expected a missed day to break the streak, got %d", current)
+	}
+}
+
+func TestStatsReportTotals(t *testing.T) {
+	now := time.Date(2026, 3, 11, 18, 0, 0, 0, time.UTC)
+	entries := []historyEntry{
+		statsEntry(time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC), 1500),
+		statsEntry(time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC), 1500),
+		statsEntry(time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), 3600),
+		statsEntry(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), 600),
+		statsEntry(time.Date(2026, 2, 27, 9, 0, 0, 0, time.UTC), 600),
+	}
+
+	report := statsReport(entries, now, 80, statsWeekly)
+	for _, want := range []string{
+		"Today            50m  (2 sessions)",
+		"This week     1h 50m  (3 sessions)",
+		"This month    2h 00m  (4 sessions)",
+		"Week of 9 Mar",
+	} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("expected %q in report:\n%s", want, report)
+		}
+	}
+}
+
+func TestHeatmapShadesDaysAndFitsWidth(t *testing.T) {
+	// Wednesday: Thursday to Sunday of this week are still ahead.
+	now := time.Date(2026, 3, 11, 18, 0, 0, 0, time.UTC)
+	daily := dailyTotals([]historyEntry{
+		statsEntry(time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC), 4000),
+		statsEntry(time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC), 1000),
+	}, time.UTC)
+
+	lines := heatmapLines(daily, now, 24)
+	if len(lines) != 9 {
+		t.Fatalf("expected a month row, seven weekday rows and a legend, got %d lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "    Jan") || !strings.Contains(lines[0], "Feb") || !strings.Contains(lines[0], "Mar") {
+		t.Fatalf("expected month labels, got %q", lines[0])
+	}
+	monday, wednesday, sunday := lines[1], lines[3], lines[7]
+	if monday != "Mon · · · · · · · · · ░" {
+		t.Fatalf("unexpected Monday row %q", monday)
+	}
+	if !strings.HasSuffix(wednesday, "█") {
+		t.Fatalf("expected today to be the busiest cell, got %q", wednesday)
+	}
+	if strings.Count(sunday, "·") != 9 {
+		t.Fatalf("expected Sunday to stop before the current week, got %q", sunday)
+	}
+}
+
+func TestStatsScreenCyclesPeriods(t *testing.T) {
+	m := newModel(testPayload())
+	m.screen = screenStats
+	m = press(m, tea.KeyMsg{Type: tea.KeyLeft})
+	if m.statsPeriod != statsMonthly {
+		t.Fatalf("expected left to wrap to the monthly table, got %s", statsPeriodNames[m.statsPeriod])
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.screen != screenMain {
+		t.Fatalf("expected esc to return to the menu")
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// lapRecord is one press of the lap key: the time since the previous lap and
+// the split, the stopwatch total at that moment.
+type lapRecord struct {
+	number int
+	lap    time.Duration
+	split  time.Duration
+}
+
+// formatLapDuration shows hundredths of a second, which the whole-second
+// clock face hides.
+func formatLapDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	centis := int(d / (10 * time.Millisecond))
+	hours := centis / 360000
+	minutes := (centis / 6000) % 60
+	seconds := (centis / 100) % 60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d.%02d", hours, minutes, seconds, centis%100)
+	}
+	return fmt.Sprintf("%02d:%02d.%02d", minutes, seconds, centis%100)
+}
+
+func bestLap(laps []lapRecord) time.Duration {
+	best := time.Duration(-1)
+	for _, lap := range laps {
+		if best < 0 || lap.lap < best {
+			best = lap.lap
+		}
+	}
+	return best
+}
+
+func lapDeltaText(lap time.Duration, best time.Duration) string {
+	if lap == best {
+		return "best"
+	}
+	return "+" + formatLapDuration(lap-best)
+}
+
+// stopwatchModel adds laps to the Go clock. Pause, restart, style and exit
+// behave exactly as in the countdown; restart also clears the laps.
+type stopwatchModel struct {
+	clock     clockModel
+	laps      []lapRecord
+	lapOffset int
+}
+
+func newStopwatchModel(payload statePayload) stopwatchModel {
+	return stopwatchModel{clock: newClockModel("stopwatch", 0, payload)}
+}
+
+func (s stopwatchModel) Init() tea.Cmd {
+	return s.clock.Init()
+}
+
+func (s *stopwatchModel) addLap() {
+	if s.clock.paused {
+		return
+	}
+	split := s.clock.elapsed()
+	previous := time.Duration(0)
+	if len(s.laps) > 0 {
+		previous = s.laps[len(s.laps)-1].split
+	}
+	s.laps = append(s.laps, lapRecord{number: len(s.laps) + 1, lap: split - previous, split: split})
+	s.lapOffset = 0
+}
+
+// lapRows is how many laps fit under the clock: up to a third of the
+// terminal, but at least a few rows so the table is worth showing.
+func (s stopwatchModel) lapRows() int {
+	height := s.clock.height
+	if height <= 0 {
+		height = fallbackFrameHeight
+	}
+	rows := height / 3
+	if rows < 3 {
+		rows = 3
+	}
+	if rows > len(s.laps) {
+		rows = len(s.laps)
+	}
+	return rows
+}
+
+func (s *stopwatchModel) scrollLaps(delta int) {
+	s.lapOffset += delta
+	if max := len(s.laps) - s.lapRows(); s.lapOffset > max {
+		s.lapOffset = max
+	}
+	if s.lapOffset < 0 {
+		s.lapOffset = 0
+	}
+}
+
+func (s stopwatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.Type {
+		case tea.KeyDown:
+			s.scrollLaps(1)
+			return s, nil
+		case tea.KeyUp:
+			s.scrollLaps(-1)
+			return s, nil
+		case tea.KeyPgDown:
+			s.scrollLaps(s.lapRows())
+			return s, nil
+		case tea.KeyPgUp:
+			s.scrollLaps(-s.lapRows())
+			return s, nil
+		}
+		switch clockKeyToken(key) {
+		case s.clock.cfg.Keybindings.LapKey:
+			s.addLap()
+			return s, nil
+		case s.clock.cfg.Keybindings.RestartKey:
+			s.laps = nil
+			s.lapOffset = 0
+		}
+	}
+	updated, cmd := s.clock.Update(msg)
+	s.clock = updated.(clockModel)
+	s.scrollLaps(0)
+	return s, cmd
+}
+
+// lapTableLines lists the visible laps, newest first.
+func (s stopwatchModel) lapTableLines(rows int) []string {
+	lines := []string{fmt.Sprintf("%4s  %12s  %12s  %12s", "Lap", "Lap time", "Split", "vs best")}
+	best := bestLap(s.laps)
+	for idx := len(s.laps) - 1 - s.lapOffset; idx >= 0 && len(lines) <= rows; idx-- {
+		lap := s.laps[idx]
+		lines = append(lines, fmt.Sprintf("%4d  %12s  %12s  %12s",
+			lap.number, formatLapDuration(lap.lap), formatLapDuration(lap.split), lapDeltaText(lap.lap, best)))
+	}
+	if len(s.laps) > rows {
+		lines[0] += fmt.Sprintf("   (%d-%d of %d, ↑/↓ to scroll)", s.lapOffset+1, s.lapOffset+rows, len(s.laps))
+	}
+	return lines
+}
+
+func (s stopwatchModel) View() string {
+	rows := s.lapRows()
+	height := s.clock.height
+	if height <= 0 {
+		height = fallbackFrameHeight
+	}
+	tableHeight := rows + 2
+	if rows == 0 || height-tableHeight < 3 {
+		return s.clock.View()
+	}
+
+	clock := s.clock
+	clock.height = height - tableHeight
+	lines := []string{clock.View(), ""}
+	for _, line := range s.lapTableLines(rows) {
+		lines = append(lines, clipLine(line, clock.width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeLapsCSV(w io.Writer, laps []lapRecord) error {
+	best := bestLap(laps)
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"lap", "lap_time", "split", "vs_best", "lap_ms", "split_ms"}); err != nil {
+		return err
+	}
+	for _, lap := range laps {
+		if err := out.Write([]string{
+			strconv.Itoa(lap.number),
+			formatLapDuration(lap.lap),
+			formatLapDuration(lap.split),
+			lapDeltaText(lap.lap, best),
+			strconv.FormatInt(lap.lap.Milliseconds(), 10),
+			strconv.FormatInt(lap.split.Milliseconds(), 10),
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+type lapExport struct {
+	Lap       int   `json:"lap"`
+	LapMs     int64 `json:"lapMs"`
+	SplitMs   int64 `json:"splitMs"`
+	VsBestMs  int64 `json:"vsBestMs"`
+	IsBestLap bool  `json:"isBestLap"`
+}
+
+func writeLapsJSON(w io.Writer, laps []lapRecord) error {
+	best := bestLap(laps)
+	export := make([]lapExport, 0, len(laps))
+	for _, lap := range laps {
+		export = append(export, lapExport{
+			Lap:       lap.number,
+			LapMs:     lap.lap.Milliseconds(),
+			SplitMs:   lap.split.Milliseconds(),
+			VsBestMs:  (lap.lap - best).Milliseconds(),
+			IsBestLap: lap.lap == best,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{"laps": export})
+}
+
+// exportLaps writes laps to path, or stdout for "-". An empty format is
+// taken from the file extension, defaulting to CSV.
+func exportLaps(path string, format string, laps []lapRecord) error {
+	if format == "" {
+		format = "csv"
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = "json"
+		}
+	}
+	write := writeLapsCSV
+	switch strings.ToLower(format) {
+	case "csv":
+	case "json":
+		write = writeLapsJSON
+	default:
+		return fmt.Errorf("unknown lap export format %q (use csv or json)", format)
+	}
+	if path == "-" {
+		return write(os.Stdout, laps)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := write(file, laps); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// runStopwatchCommand runs `stopwatch` and exports the laps when it exits.
+func runStopwatchCommand(args []string, configPath string, fontDir string, policy configWritePolicy, admin adminPolicy) int {
+	session, args, err := extractSessionTags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	flags := flag.NewFlagSet("stopwatch", flag.ContinueOnError)
+	exportPath := flags.String("export", "-", "Write laps to this file on exit (.csv or .json; - for stdout)")
+	format := flags.String("format", "", "Lap export format, csv or json (default: from the --export extension)")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n\n%s\n", strings.Join(flags.Args(), " "), commandUsage)
+		return 1
+	}
+	// Laps go to stdout by default, so `stopwatch > laps.csv` draws on stderr.
+	output := os.Stdout
+	if !term.IsTerminal(int(output.Fd())) {
+		output = os.Stderr
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(output.Fd())) {
+		fmt.Fprintln(os.Stderr, "The stopwatch requires an interactive terminal (TTY).")
+		return 1
+	}
+	payload, err := loadStandalonePayload(configPath, fontDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	payload.Config = admin.apply(payload.Config)
+
+	stopwatch := newStopwatchModel(payload)
+	stopwatch.clock.writePolicy = policy
+	stopwatch.clock.admin = admin
+	stopwatch.clock.title = session.label
+	stopwatch.clock.tags = session.tags
+	finalModel, err := tea.NewProgram(stopwatch, tea.WithAltScreen(), tea.WithOutput(output)).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Stopwatch failed: %v\n", err)
+		return 1
+	}
+	final, ok := finalModel.(stopwatchModel)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Stopwatch exited unexpectedly")
+		return 1
+	}
+	recordSessions(payload, final.clock.historyEntry(session.label))
+	if len(final.laps) == 0 {
+		return 0
+	}
+	if err := exportLaps(*exportPath, *format, final.laps); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to export laps: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testStopwatch() (stopwatchModel, *fakeClock) {
+	s := newStopwatchModel(testPayload())
+	return s, newFakeClock(&s.clock)
+}
+
+var lapKeyMsg = runeKey("l")
+
+func TestStopwatchRecordsLapsAndSplits(t *testing.T) {
+	s, fake := testStopwatch()
+	for _, step := range []time.Duration{1500 * time.Millisecond, 1200 * time.Millisecond, 2 * time.Second} {
+		fake.t = fake.t.Add(step)
+		s = press(s, lapKeyMsg)
+	}
+	if len(s.laps) != 3 {
+		t.Fatalf("expected 3 laps, got %d", len(s.laps))
+	}
+	if s.laps[1].lap != 1200*time.Millisecond || s.laps[1].split != 2700*time.Millisecond {
+		t.Fatalf("unexpected second lap %+v", s.laps[1])
+	}
+	if got := lapDeltaText(s.laps[2].lap, bestLap(s.laps)); got != "+00:00.80" {
+		t.Fatalf("expected delta from best lap, got %q", got)
+	}
+
+	s = press(s, runeKey(" "))
+	s = press(s, lapKeyMsg)
+	if len(s.laps) != 3 {
+		t.Fatalf("expected no lap while paused")
+	}
+
+	s = press(s, runeKey("r"))
+	if len(s.laps) != 0 || s.clock.displaySeconds() != 0 {
+		t.Fatalf("expected restart to clear laps and time")
+	}
+}
+
+func TestStopwatchLapTableScrolls(t *testing.T) {
+	s, fake := testStopwatch()
+	updated, _ := s.Update(tea.WindowSizeMsg{Width: 80, Height: 15})
+	s = updated.(stopwatchModel)
+	for i := 0; i < 8; i++ {
+		fake.t = fake.t.Add(time.Second)
+		s = press(s, lapKeyMsg)
+	}
+
+	view := s.View()
+	if lines := strings.Split(view, "\n"); len(lines) != 15 {
+		t.Fatalf("expected the view to fill 15 lines, got %d", len(lines))
+	}
+	if !strings.Contains(view, "   8      00:01.00") || strings.Contains(view, "   1      00:01.00") {
+		t.Fatalf("expected newest laps first:\n%s", view)
+	}
+
+	for i := 0; i < 10; i++ {
+		s = press(s, tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if s.lapOffset != 3 {
+		t.Fatalf("expected scrolling to stop at the oldest lap, got offset %d", s.lapOffset)
+	}
+	if !strings.Contains(s.View(), "   1      00:01.00") {
+		t.Fatalf("expected the oldest lap after scrolling")
+	}
+}
+
+func TestStopwatchControlsShowLapKey(t *testing.T) {
+	s, _ := testStopwatch()
+	if !strings.Contains(s.View(), "| l Lap") {
+		t.Fatalf("expected the lap key in the controls line")
+	}
+}
+
+func TestExportLaps(t *testing.T) {
+	laps := []lapRecord{
+		{number: 1, lap: 2 * time.Second, split: 2 * time.Second},
+		{number: 2, lap: 1500 * time.Millisecond, split: 3500 * time.Millisecond},
+	}
+
+	var csvOut bytes.Buffer
+	if err := writeLapsCSV(&csvOut, laps); err != nil {
+		t.Fatal(err)
+	}
+	want := "lap,lap_time,split,vs_best,lap_ms,split_ms\n1,00:02.00,00:02.00,+00:00.50,2000,2000\n2,00:01.50,00:03.50,best,1500,3500\n"
+	if csvOut.String() != want {
+		t.Fatalf("unexpected CSV:\n%s", csvOut.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "laps.json")
+	if err := exportLaps(path, "", laps); err != nil {
+		t.Fatal(err)
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Laps []lapExport `json:"laps"`
+	}
+	if err := json.Unmarshal(text, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Laps) != 2 || decoded.Laps[0].VsBestMs != 500 || !decoded.Laps[1].IsBestLap {
+		t.Fatalf("unexpected JSON export %+v", decoded.Laps)
+	}
+
+	if err := exportLaps(path, "xml", laps); err == nil {
+		t.Fatalf("expected unknown format to be rejected")
+	}
+}
+
+func TestKeymapPresetWithoutLapKeyLoads(t *testing.T) {
+	dir := t.TempDir()
+	text := `{"name": "Old", "keybindings": {"pauseKey": "p", "pauseAltKey": "space", "restartKey": "r", "styleKey": "f", "exitKey": "q", "exitAltKey": "e"}}`
+	if err := os.WriteFile(filepath.Join(dir, "old.json"), []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	presets, err := loadKeymapPresetFiles(dir)
+	if err != nil || len(presets) != 1 || presets[0].Keybindings != defaultKeybindings {
+		t.Fatalf("expected old preset to load with the default lap key, got %+v (%v)", presets, err)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	tickRateStepMs      = 10
+	tickRateLargeStepMs = 100
+	tickRateSliderWidth = 40
+)
+
+// tickSampleMsg advances the live sample in the tick rate editor. id ties
+// the message to the sample that scheduled it, so changing the rate drops
+// ticks still in flight for the old rate.
+type tickSampleMsg struct {
+	id int
+}
+
+func tickSampleCmd(id int, rateMs int) tea.Cmd {
+	return tea.Tick(time.Duration(rateMs)*time.Millisecond, func(time.Time) tea.Msg {
+		return tickSampleMsg{id: id}
+	})
+}
+
+// editedTickRate is the value in the editor, or the saved value while the
+// input does not hold a valid number.
+func (m model) editedTickRate() int {
+	value, err := strconv.Atoi(strings.TrimSpace(m.tickInput.Value()))
+	if err != nil || value < minTickRateMs || value > maxTickRateMs {
+		return m.payload.Config.TickRateMs
+	}
+	return value
+}
+
+func (m *model) restartTickSample() tea.Cmd {
+	m.tickSampleID++
+	m.tickSampleFrames = 0
+	return tickSampleCmd(m.tickSampleID, m.editedTickRate())
+}
+
+func (m *model) openTickRateEditor() tea.Cmd {
+	m.tickInput.SetValue(strconv.Itoa(m.payload.Config.TickRateMs))
+	m.tickInput.CursorEnd()
+	m.tickInput.Focus()
+	m.screen = screenTickRateEditor
+	return m.restartTickSample()
+}
+
+func (m *model) adjustTickRate(delta int) tea.Cmd {
+	value := sanitizeTickRate(m.editedTickRate() + delta)
+	m.tickInput.SetValue(strconv.Itoa(value))
+	m.tickInput.CursorEnd()
+	m.err = nil
+	return m.restartTickSample()
+}
+
+func tickRateSlider(value int, width int) string {
+	span := maxTickRateMs - minTickRateMs
+	pos := (sanitizeTickRate(value) - minTickRateMs) * (width - 1) / span
+	return fmt.Sprintf("%d ms [%s|%s] %d ms", minTickRateMs, strings.Repeat("=", pos), strings.Repeat("-", width-1-pos), maxTickRateMs)
+}
+
+func tickRateCostNote(value int) string {
+	perSecond := 1000.0 / float64(sanitizeTickRate(value))
+	var note string
+	switch {
+	case value <= 75:
+		note = "highest CPU wake-ups; snappiest pause/resume, noticeable on battery"
+	case value <= 150:
+		note = "low CPU; smooth and responsive (default range)"
+	case value <= 400:
+		note = "very low CPU; pause/resume can lag a few tenths of a second"
+	default:
+		note = "minimal CPU, best for battery; seconds may visibly land late"
+	}
+	return fmt.Sprintf("~%.1f redraw checks per second: %s", perSecond, note)
+}
+
+// tickSampleView shows a clock that only advances when a tick fires, the way
+// the real timer does, next to a marker sweeping once per second.
+func (m model) tickSampleView() string {
+	rate := m.editedTickRate()
+	elapsedMs := m.tickSampleFrames * rate
+	slots := 1000 / rate
+	if slots < 1 {
+		slots = 1
+	}
+	pos := m.tickSampleFrames % slots
+	marker := strings.Repeat(" ", pos) + "*" + strings.Repeat(" ", slots-1-pos)
+	return fmt.Sprintf("Live sample: %s.%d  [%s]", formatHms(elapsedMs/1000), (elapsedMs%1000)/100, marker)
+}
+
+func (m model) tickRateEditorView(errorLine string, footer string) string {
+	value := m.editedTickRate()
+	return fmt.Sprintf(
+		"Tick rate (%d-%d ms)\n\n%s\n\n%s\n%s\n%s\n%s\n\n%s",
+		minTickRateMs,
+		maxTickRateMs,
+		m.tickInput.View(),
+		tickRateSlider(value, tickRateSliderWidth),
+		tickRateCostNote(value),
+		m.tickSampleView(),
+		errorLine,
+		footer,
+	)
+}
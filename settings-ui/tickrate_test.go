@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTickRateStepKeysClampToRange(t *testing.T) {
+	m := newModel(testPayload())
+	m.openTickRateEditor()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	next := updated.(model)
+	if next.tickInput.Value() != "110" {
+		t.Fatalf("expected right to add %d ms, got %q", tickRateStepMs, next.tickInput.Value())
+	}
+
+	for i := 0; i < 12; i++ {
+		updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+		next = updated.(model)
+	}
+	if next.tickInput.Value() != "1000" {
+		t.Fatalf("expected shift+right to clamp at %d, got %q", maxTickRateMs, next.tickInput.Value())
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := updated.(model).payload.Config.TickRateMs; got != maxTickRateMs {
+		t.Fatalf("expected saved tick rate %d, got %d", maxTickRateMs, got)
+	}
+}
+
+func TestTickSampleIgnoresStaleTicks(t *testing.T) {
+	m := newModel(testPayload())
+	m.openTickRateEditor()
+	staleID := m.tickSampleID
+	m.adjustTickRate(tickRateStepMs)
+
+	updated, cmd := m.Update(tickSampleMsg{id: staleID})
+	if updated.(model).tickSampleFrames != 0 || cmd != nil {
+		t.Fatalf("expected stale tick to be dropped")
+	}
+
+	updated, cmd = m.Update(tickSampleMsg{id: m.tickSampleID})
+	if updated.(model).tickSampleFrames != 1 || cmd == nil {
+		t.Fatalf("expected current tick to advance the sample and reschedule")
+	}
+}
+
+func TestTickRateSliderPositions(t *testing.T) {
+	if got := tickRateSlider(minTickRateMs, 5); got != "50 ms [|----] 1000 ms" {
+		t.Fatalf("unexpected slider at minimum: %q", got)
+	}
+	if got := tickRateSlider(maxTickRateMs, 5); got != "50 ms [====|] 1000 ms" {
+		t.Fatalf("unexpected slider at maximum: %q", got)
+	}
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"cli-timer-settings-ui/duration"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	maxTimerPresets       = 50
+	maxTimerPresetNameLen = 40
+)
+
+// timerPreset is a named duration from the "presets" list in the config,
+// started with `timer preset <name>`. Font and message, when set, replace
+// the configured ones for that timer.
+type timerPreset struct {
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+	Font     string `json:"font,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// parsePresetDuration reads a preset's duration text; presets hold a
+// length of time, so `until` targets are refused.
+func parsePresetDuration(text string) (duration.Spec, error) {
+	spec, err := duration.Parse(strings.Fields(text), time.Now())
+	if err != nil {
+		return duration.Spec{}, err
+	}
+	if spec.IsTarget() {
+		return duration.Spec{}, errors.New("presets take a length of time such as 25 min, not a target")
+	}
+	return spec, nil
+}
+
+// validateTimerPreset checks a preset as entered in the editor; other holds
+// the remaining presets, whose names must stay unique.
+func validateTimerPreset(p timerPreset, other []timerPreset, fonts []string) error {
+	switch {
+	case p.Name == "":
+		return errors.New("name is required")
+	case len([]rune(p.Name)) > maxTimerPresetNameLen:
+		return fmt.Errorf("name must be at most %d characters", maxTimerPresetNameLen)
+	}
+	if _, ok := findTimerPreset(other, p.Name); ok {
+		return fmt.Errorf("a preset named %q already exists", p.Name)
+	}
+	if _, err := parsePresetDuration(p.Duration); err != nil {
+		return fmt.Errorf("duration: %w", err)
+	}
+	if p.Font != "" && len(fonts) > 0 && !containsString(fonts, p.Font) {
+		return fmt.Errorf("unknown font %q", p.Font)
+	}
+	return nil
+}
+
+// normalizeTimerPresets drops presets without a name or a valid duration
+// and later ones whose name is taken, so a hand-edited config cannot make
+// `timer preset` ambiguous.
+func normalizeTimerPresets(presets []timerPreset) []timerPreset {
+	result := []timerPreset{}
+	for _, p := range presets {
+		p.Name = strings.TrimSpace(p.Name)
+		p.Duration = strings.Join(strings.Fields(p.Duration), " ")
+		p.Font = strings.TrimSpace(p.Font)
+		p.Message = normalizeCompletionMessage(p.Message)
+		if p.Name == "" || len([]rune(p.Name)) > maxTimerPresetNameLen {
+			continue
+		}
+		if _, err := parsePresetDuration(p.Duration); err != nil {
+			continue
+		}
+		if _, taken := findTimerPreset(result, p.Name); taken || len(result) == maxTimerPresets {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// findTimerPreset looks a preset up by name, ignoring case.
+func findTimerPreset(presets []timerPreset, name string) (timerPreset, bool) {
+	for _, p := range presets {
+		if strings.EqualFold(p.Name, strings.TrimSpace(name)) {
+			return p, true
+		}
+	}
+	return timerPreset{}, false
+}
+
+func timerPresetsSummary(presets []timerPreset) string {
+	switch len(presets) {
+	case 0:
+		return "None"
+	case 1, 2, 3:
+		names := make([]string, 0, len(presets))
+		for _, p := range presets {
+			names = append(names, p.Name)
+		}
+		return strings.Join(names, ", ")
+	default:
+		return fmt.Sprintf("%s, %s, %s and %d more", presets[0].Name, presets[1].Name, presets[2].Name, len(presets)-3)
+	}
+}
+
+type timerPresetEntry struct {
+	preset timerPreset
+}
+
+func (e timerPresetEntry) Title() string { return e.preset.Name }
+func (e timerPresetEntry) Description() string {
+	parts := []string{e.preset.Duration}
+	if e.preset.Font != "" {
+		parts = append(parts, "Font: "+e.preset.Font)
+	}
+	if e.preset.Message != "" {
+		parts = append(parts, summarizeMessage(e.preset.Message))
+	}
+	return strings.Join(parts, " | ")
+}
+func (e timerPresetEntry) FilterValue() string { return e.preset.Name }
+
+func buildTimerPresetItems(presets []timerPreset) []list.Item {
+	items := make([]list.Item, 0, len(presets))
+	for _, p := range presets {
+		items = append(items, timerPresetEntry{preset: p})
+	}
+	return items
+}
+
+// timerPresetForm is the add/edit form. index is the preset being edited,
+// or -1 for a new one.
+type timerPresetForm struct {
+	index  int
+	focus  int
+	inputs []textinput.Model
+}
+
+const (
+	presetFieldName = iota
+	presetFieldDuration
+	presetFieldFont
+	presetFieldMessage
+)
+
+func newTimerPresetForm(index int, p timerPreset) timerPresetForm {
+	prompts := []string{"Name: ", "Duration: ", "Font: ", "Message: "}
+	placeholders := []string{"tea", "5 min, 1h30m, 25:00", "default font", "default completion message"}
+	values := []string{p.Name, p.Duration, p.Font, p.Message}
+	limits := []int{maxTimerPresetNameLen, 64, 64, 240}
+	form := timerPresetForm{index: index}
+	for idx := range prompts {
+		input := textinput.New()
+		input.Prompt = prompts[idx]
+		input.Placeholder = placeholders[idx]
+		input.CharLimit = limits[idx]
+		input.SetValue(values[idx])
+		input.CursorEnd()
+		form.inputs = append(form.inputs, input)
+	}
+	form.inputs[0].Focus()
+	return form
+}
+
+func (f *timerPresetForm) setFocus(focus int) {
+	count := len(f.inputs)
+	f.inputs[f.focus].Blur()
+	f.focus = ((focus % count) + count) % count
+	f.inputs[f.focus].Focus()
+}
+
+func (f timerPresetForm) preset() timerPreset {
+	return timerPreset{
+		Name:     strings.TrimSpace(f.inputs[presetFieldName].Value()),
+		Duration: strings.Join(strings.Fields(f.inputs[presetFieldDuration].Value()), " "),
+		Font:     strings.TrimSpace(f.inputs[presetFieldFont].Value()),
+		Message:  normalizeCompletionMessage(f.inputs[presetFieldMessage].Value()),
+	}
+}
+
+func (m *model) openTimerPresetList() {
+	m.timerPresetList.SetItems(buildTimerPresetItems(m.payload.Config.Presets))
+	m.screen = screenTimerPresetList
+}
+
+func (m *model) openTimerPresetForm(index int) {
+	p := timerPreset{}
+	if index >= 0 {
+		p = m.payload.Config.Presets[index]
+	}
+	m.timerPresetForm = newTimerPresetForm(index, p)
+	m.err = nil
+	m.screen = screenTimerPresetForm
+}
+
+// moveTimerPreset swaps the selected preset with its neighbour.
+func (m *model) moveTimerPreset(delta int) {
+	presets := m.payload.Config.Presets
+	from := m.timerPresetList.Index()
+	to := from + delta
+	if from < 0 || from >= len(presets) || to < 0 || to >= len(presets) {
+		return
+	}
+	presets[from], presets[to] = presets[to], presets[from]
+	m.timerPresetList.SetItems(buildTimerPresetItems(presets))
+	m.timerPresetList.Select(to)
+}
+
+func (m *model) deleteTimerPreset() {
+	presets := m.payload.Config.Presets
+	index := m.timerPresetList.Index()
+	if index < 0 || index >= len(presets) {
+		return
+	}
+	m.notice = fmt.Sprintf("Deleted preset %q (not saved yet)", presets[index].Name)
+	m.payload.Config.Presets = append(presets[:index:index], presets[index+1:]...)
+	m.timerPresetList.SetItems(buildTimerPresetItems(m.payload.Config.Presets))
+	if index >= len(m.payload.Config.Presets) && index > 0 {
+		m.timerPresetList.Select(index - 1)
+	}
+}
+
+func (m *model) updateTimerPresetList(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.screen = screenMain
+		m.refreshMenu()
+		return nil
+	case isConfirmKey(msg):
+		if len(m.payload.Config.Presets) > 0 {
+			m.openTimerPresetForm(m.timerPresetList.Index())
+		}
+		return nil
+	case key.Matches(msg, m.keys.AddItem):
+		if len(m.payload.Config.Presets) >= maxTimerPresets {
+			m.err = fmt.Errorf("at most %d presets", maxTimerPresets)
+			return nil
+		}
+		m.openTimerPresetForm(-1)
+		return nil
+	case key.Matches(msg, m.keys.DeleteItem):
+		m.deleteTimerPreset()
+		return nil
+	case key.Matches(msg, m.keys.MoveItemUp):
+		m.moveTimerPreset(-1)
+		return nil
+	case key.Matches(msg, m.keys.MoveItemDown):
+		m.moveTimerPreset(1)
+		return nil
+	}
+	var cmd tea.Cmd
+	m.timerPresetList, cmd = m.timerPresetList.Update(msg)
+	return cmd
+}
+
+func (m *model) updateTimerPresetForm(msg tea.KeyMsg) tea.Cmd {
+	form := &m.timerPresetForm
+	switch {
+	case key.Matches(msg, m.keys.textBack()):
+		m.err = nil
+		m.screen = screenTimerPresetList
+		return nil
+	case key.Matches(msg, m.keys.NextField):
+		form.setFocus(form.focus + 1)
+		return nil
+	case key.Matches(msg, m.keys.PrevField):
+		form.setFocus(form.focus - 1)
+		return nil
+	case isConfirmKey(msg):
+		p := form.preset()
+		presets := m.payload.Config.Presets
+		other := make([]timerPreset, 0, len(presets))
+		for idx, existing := range presets {
+			if idx != form.index {
+				other = append(other, existing)
+			}
+		}
+		if err := validateTimerPreset(p, other, m.payload.Fonts); err != nil {
+			m.err = err
+			return nil
+		}
+		selected := form.index
+		if selected < 0 {
+			m.payload.Config.Presets = append(presets, p)
+			selected = len(m.payload.Config.Presets) - 1
+		} else {
+			presets[selected] = p
+		}
+		m.err = nil
+		m.timerPresetList.SetItems(buildTimerPresetItems(m.payload.Config.Presets))
+		m.timerPresetList.Select(selected)
+		m.screen = screenTimerPresetList
+		return nil
+	}
+	var cmd tea.Cmd
+	form.inputs[form.focus], cmd = form.inputs[form.focus].Update(msg)
+	return cmd
+}
+
+func (m model) timerPresetListBindings() []key.Binding {
+	return []key.Binding{
+		withHelpDesc(m.keys.Confirm, "edit"),
+		m.keys.AddItem, m.keys.DeleteItem, m.keys.MoveItemUp, m.keys.MoveItemDown, m.keys.Back,
+	}
+}
+
+func (m model) timerPresetListView(errorLine string, footer string) string {
+	if len(m.payload.Config.Presets) == 0 {
+		return fmt.Sprintf("Timer presets\n\nNo presets yet. Press %s to add one, then start it with `timer preset <name>`.\n%s\n%s",
+			m.keys.AddItem.Help().Key, errorLine, footer)
+	}
+	return m.timerPresetList.View() + errorLine + "\n" + footer
+}
+
+func (m model) timerPresetFormView(errorLine string, footer string) string {
+	form := m.timerPresetForm
+	title := "New preset"
+	if form.index >= 0 {
+		title = "Edit preset"
+	}
+	lines := make([]string, 0, len(form.inputs)+1)
+	for _, input := range form.inputs {
+		lines = append(lines, input.View())
+	}
+	preview := ""
+	if spec, err := parsePresetDuration(form.inputs[presetFieldDuration].Value()); err == nil {
+		preview = "Runs for " + formatClock(spec.Seconds(time.Now()), m.payload.Config.DayFormat)
+	}
+	return fmt.Sprintf("%s\n\n%s\n\n%s\n%s\n\n%s", title, strings.Join(lines, "\n"), preview, errorLine, footer)
+}
+
+// runPresetCommand starts `preset <name>`; without a name it lists the
+// presets.
+func runPresetCommand(args []string, configPath string, fontDir string, policy configWritePolicy, admin adminPolicy) int {
+	session, args, err := extractSessionTags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, commandUsage)
+		return 1
+	}
+	payload, err := loadStandalonePayload(configPath, fontDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	presets := payload.Config.Presets
+	if len(args) == 0 {
+		if len(presets) == 0 {
+			fmt.Println("No presets yet. Add them in `timer settings`.")
+			return 0
+		}
+		for _, p := range presets {
+			fmt.Printf("%s\t%s\n", p.Name, p.Duration)
+		}
+		return 0
+	}
+
+	name := strings.Join(args, " ")
+	p, ok := findTimerPreset(presets, name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown preset %q. Run `timer preset` to list them.\n", name)
+		return 1
+	}
+	spec, err := parsePresetDuration(p.Duration)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Preset %q: %v\n", p.Name, err)
+		return 1
+	}
+	if p.Font != "" {
+		payload.Config.Font = p.Font
+	}
+	if p.Message != "" {
+		payload.Config.CompletionMessage = p.Message
+	}
+	payload.Config = admin.apply(payload.Config)
+	if session.label == "" {
+		session.label = p.Name
+	}
+	return runCountdown(payload, spec, wallNow(), session, policy, admin)
+}
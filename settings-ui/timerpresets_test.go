@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNormalizeTimerPresetsDropsInvalidAndDuplicates(t *testing.T) {
+	got := normalizeTimerPresets([]timerPreset{
+		{Name: " tea ", Duration: "4   min"},
+		{Name: "", Duration: "5 min"},
+		{Name: "bad", Duration: "5 parsecs"},
+		{Name: "Tea", Duration: "3 min"},
+		{Name: "meeting", Duration: "until 17:00"},
+		{Name: "build", Duration: "1h30m", Font: "Big", Message: "line\nbreak"},
+	})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 presets to survive, got %+v", got)
+	}
+	if got[0] != (timerPreset{Name: "tea", Duration: "4 min"}) {
+		t.Fatalf("unexpected first preset %+v", got[0])
+	}
+	if got[1].Name != "build" || got[1].Message != "line break" {
+		t.Fatalf("unexpected second preset %+v", got[1])
+	}
+	if p, ok := findTimerPreset(got, "BUILD"); !ok || p.Duration != "1h30m" {
+		t.Fatalf("expected a case-insensitive lookup, got %+v %v", p, ok)
+	}
+}
+
+func TestValidateTimerPreset(t *testing.T) {
+	existing := []timerPreset{{Name: "tea", Duration: "4 min"}}
+	fonts := []string{"Standard", "Big"}
+	cases := []struct {
+		preset timerPreset
+		want   string
+	}{
+		{timerPreset{Duration: "5 min"}, "name is required"},
+		{timerPreset{Name: "TEA", Duration: "5 min"}, "already exists"},
+		{timerPreset{Name: "nap", Duration: "20 parsecs"}, "duration: unknown unit"},
+		{timerPreset{Name: "nap", Duration: "at 9am"}, "not a target"},
+		{timerPreset{Name: "nap", Duration: "20 min", Font: "Nope"}, "unknown font"},
+		{timerPreset{Name: "nap", Duration: "20 min", Font: "Big"}, ""},
+	}
+	for _, tc := range cases {
+		err := validateTimerPreset(tc.preset, existing, fonts)
+		if tc.want == "" && err != nil || tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)) {
+			t.Fatalf("validateTimerPreset(%+v) = %v, want %q", tc.preset, err, tc.want)
+		}
+	}
+}
+
+func TestTimerPresetEditorAddsReordersAndDeletes(t *testing.T) {
+	payload := testPayload()
+	payload.Config.Presets = []timerPreset{{Name: "tea", Duration: "4 min"}}
+	m := newModel(payload)
+	m.openTimerPresetList()
+
+	m = press(m, runeKey("a"))
+	if m.screen != screenTimerPresetForm || m.timerPresetForm.index != -1 {
+		t.Fatalf("expected a to open an empty form, got screen %v", m.screen)
+	}
+	m = press(m, runeKey("laundry"))
+	m = press(m, tea.KeyMsg{Type: tea.KeyTab})
+	m = press(m, runeKey("1h 5"))
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err == nil || m.screen != screenTimerPresetForm {
+		t.Fatalf("expected an invalid duration to keep the form open")
+	}
+	m = press(m, runeKey("m"))
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil || m.screen != screenTimerPresetList {
+		t.Fatalf("expected the preset to be saved, got %v", m.err)
+	}
+	if got := m.payload.Config.Presets; len(got) != 2 || got[1] != (timerPreset{Name: "laundry", Duration: "1h 5m"}) {
+		t.Fatalf("unexpected presets %+v", got)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyShiftUp})
+	if m.payload.Config.Presets[0].Name != "laundry" || m.timerPresetList.Index() != 0 {
+		t.Fatalf("expected laundry to move to the top, got %+v", m.payload.Config.Presets)
+	}
+
+	m = press(m, runeKey("x"))
+	if len(m.payload.Config.Presets) != 1 || m.payload.Config.Presets[0].Name != "tea" {
+		t.Fatalf("expected laundry to be deleted, got %+v", m.payload.Config.Presets)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.screen != screenMain {
+		t.Fatalf("expected esc to return to the menu, got %v", m.screen)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	// Windows and minimal containers ship without a zoneinfo database.
+	_ "time/tzdata"
+)
+
+const (
+	dayFormatDays  = "days"
+	dayFormatHours = "hours"
+)
+
+// normalizeDayFormat falls back to showing days for unknown values.
+func normalizeDayFormat(value string) string {
+	if strings.TrimSpace(strings.ToLower(value)) == dayFormatHours {
+		return dayFormatHours
+	}
+	return dayFormatDays
+}
+
+func dayFormatLabel(value string) string {
+	if value == dayFormatHours {
+		return "Hours only (30:00:00)"
+	}
+	return "Days past 24h (1d 06:00:00)"
+}
+
+// formatClock is formatHms with the configured day format: past 24 hours
+// the days are split off unless the user prefers counting hours.
+func formatClock(totalSeconds int, dayFormat string) string {
+	if dayFormat == dayFormatHours || totalSeconds < 86400 {
+		return formatHms(totalSeconds)
+	}
+	return fmt.Sprintf("%dd %s", totalSeconds/86400, formatHms(totalSeconds%86400))
+}
+
+// loadTimeZone resolves an IANA zone name; empty means the system zone.
+func loadTimeZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q (use a name like Europe/Berlin)", name)
+	}
+	return loc, nil
+}
+
+// normalizeTimeZone drops zones this system cannot resolve, so a config
+// written elsewhere falls back to local time instead of failing.
+func normalizeTimeZone(name string) string {
+	name = strings.TrimSpace(name)
+	if _, err := loadTimeZone(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+func timeZoneLabel(name string) string {
+	if name == "" {
+		return "System local"
+	}
+	return name
+}
+
+func newTimeZoneInput(value string) textinput.Model {
+	input := textinput.New()
+	input.Prompt = "Time zone: "
+	input.Placeholder = "System local"
+	input.CharLimit = 64
+	input.SetValue(value)
+	input.Blur()
+	return input
+}
+
+func (m *model) openTimeZoneEditor() {
+	m.tzInput.SetValue(m.payload.Config.TimeZone)
+	m.tzInput.CursorEnd()
+	m.tzInput.Focus()
+	m.screen = screenTimeZoneEditor
+}
+
+func (m *model) updateTimeZoneEditor(msg tea.KeyMsg) tea.Cmd {
+	if isConfirmKey(msg) {
+		value := strings.TrimSpace(m.tzInput.Value())
+		if _, err := loadTimeZone(value); err != nil {
+			m.err = err
+			return nil
+		}
+		m.payload.Config.TimeZone = value
+		m.err = nil
+		m.tzInput.Blur()
+		m.screen = screenMain
+		m.refreshMenu()
+		return nil
+	}
+	var cmd tea.Cmd
+	m.tzInput, cmd = m.tzInput.Update(msg)
+	return cmd
+}
+
+// timeZoneEditorView previews the current time in the zone being typed.
+func (m model) timeZoneEditorView(errorLine string, footer string) string {
+	preview := "Unknown time zone"
+	if loc, err := loadTimeZone(m.tzInput.Value()); err == nil {
+		preview = "Now there: " + time.Now().In(loc).Format("Mon 2 Jan 15:04 MST")
+	}
+	return fmt.Sprintf("Default time zone for `timer until`\n\n%s\n%s\n%s\n\n%s",
+		m.tzInput.View(), preview, errorLine, footer)
+}
+
+// targetSubtitle names the target in the clock header.
+func targetSubtitle(target time.Time) string {
+	return "until " + target.Format("Mon 2 Jan 2006 15:04 MST")
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFormatClockSplitsDays(t *testing.T) {
+	cases := []struct {
+		seconds int
+		format  string
+		want    string
+	}{
+		{3661, dayFormatDays, "01:01:01"},
+		{86399, dayFormatDays, "23:59:59"},
+		{86400, dayFormatDays, "1d 00:00:00"},
+		{30 * 3600, dayFormatDays, "1d 06:00:00"},
+		{30 * 3600, dayFormatHours, "30:00:00"},
+	}
+	for _, tc := range cases {
+		if got := formatClock(tc.seconds, tc.format); got != tc.want {
+			t.Fatalf("formatClock(%d, %q) = %q, want %q", tc.seconds, tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeConfigTimeSettings(t *testing.T) {
+	cfg := normalizeConfig(config{TimeZone: " Europe/Berlin ", DayFormat: "HOURS"})
+	if cfg.TimeZone != "Europe/Berlin" || cfg.DayFormat != dayFormatHours {
+		t.Fatalf("unexpected time settings %q %q", cfg.TimeZone, cfg.DayFormat)
+	}
+	cfg = normalizeConfig(config{TimeZone: "Mars/Olympus", DayFormat: "weeks"})
+	if cfg.TimeZone != "" || cfg.DayFormat != dayFormatDays {
+		t.Fatalf("expected fallbacks, got %q %q", cfg.TimeZone, cfg.DayFormat)
+	}
+}
+
+func TestTimeZoneEditorValidates(t *testing.T) {
+	m := newModel(testPayload())
+	m.openTimeZoneEditor()
+	m.tzInput.SetValue("Mars/Olympus")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+	if next.screen != screenTimeZoneEditor || next.err == nil {
+		t.Fatalf("expected an unknown zone to be rejected")
+	}
+	if !strings.Contains(next.View(), "Unknown time zone") {
+		t.Fatalf("expected the preview to flag the zone")
+	}
+
+	next.tzInput.SetValue("America/New_York")
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next = updated.(model)
+	if next.screen != screenMain || next.payload.Config.TimeZone != "America/New_York" {
+		t.Fatalf("expected the zone to be saved, got %q", next.payload.Config.TimeZone)
+	}
+}
+
+func TestDayFormatMenuToggle(t *testing.T) {
+	m := newModel(testPayload())
+	m.jumpToMenuEntry("dayFormat")
+	m.applyMenuAction()
+	if m.payload.Config.DayFormat != dayFormatHours {
+		t.Fatalf("expected hours, got %q", m.payload.Config.DayFormat)
+	}
+}
+
+func TestClockCountsDownToTarget(t *testing.T) {
+	c, fake := testClock(0)
+	c.target = fake.t.Add(50 * time.Hour)
+	c.cfg.DayFormat = dayFormatDays
+	if got := c.displaySeconds(); got != 50*3600 {
+		t.Fatalf("expected 50 hours left, got %d", got)
+	}
+
+	// Pausing a deadline is meaningless; time keeps passing.
+	c = press(c, runeKey(" "))
+	fake.t = fake.t.Add(2 * time.Hour)
+	if c.paused || c.displaySeconds() != 48*3600 {
+		t.Fatalf("expected pause to be ignored, got %d", c.displaySeconds())
+	}
+
+	// A suspended machine wakes up with the wall clock far ahead.
+	fake.t = c.target.Add(-90 * time.Second)
+	if got := c.displaySeconds(); got != 90 {
+		t.Fatalf("expected 90 seconds after wake, got %d", got)
+	}
+
+	c.cfg.ShowControls = false
+	view := c.View()
+	if !strings.Contains(view, "Timer until ") {
+		t.Fatalf("expected the target in the header:\n%s", view)
+	}
+}
+
+func TestExtractFlag(t *testing.T) {
+	values, rest, err := extractFlag([]string{"until", "17:30", "--tz", "Asia/Tokyo", "--tz=UTC"}, "tz")
+	if err != nil || strings.Join(values, ",") != "Asia/Tokyo,UTC" || strings.Join(rest, " ") != "until 17:30" {
+		t.Fatalf("unexpected split %v %v %v", values, rest, err)
+	}
+	if _, _, err := extractFlag([]string{"5", "min", "--tz"}, "tz"); err == nil {
+		t.Fatalf("expected a missing value to be reported")
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	uiStateFileName = "settings-ui-state.json"
+	maxRecentFonts  = 8
+)
+
+// uiState is where the previous session left the settings UI. It is a
+// convenience only: a missing or broken file just means a fresh start.
+type uiState struct {
+	MenuItem     string   `json:"menuItem"`
+	FontFilter   string   `json:"fontFilter"`
+	FontSelected string   `json:"fontSelected"`
+	RecentFonts  []string `json:"recentFonts"`
+	FontSort     string   `json:"fontSort"`
+}
+
+func uiStatePath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), uiStateFileName)
+}
+
+func loadUIState(path string) uiState {
+	var state uiState
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return uiState{}
+	}
+	if err := json.Unmarshal(text, &state); err != nil {
+		return uiState{}
+	}
+	if len(state.RecentFonts) > maxRecentFonts {
+		state.RecentFonts = state.RecentFonts[:maxRecentFonts]
+	}
+	return state
+}
+
+// addRecentFont moves font to the front of recent, dropping the oldest
+// entries past maxRecentFonts.
+func addRecentFont(recent []string, font string) []string {
+	next := []string{font}
+	for _, name := range recent {
+		if name != font && len(next) < maxRecentFonts {
+			next = append(next, name)
+		}
+	}
+	return next
+}
+
+func runFilterCmd(l list.Model, cmd tea.Cmd) list.Model {
+	if cmd == nil {
+		return l
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, inner := range msg {
+			l = runFilterCmd(l, inner)
+		}
+	case list.FilterMatchesMsg:
+		l, _ = l.Update(msg)
+	}
+	return l
+}
+
+// restoreListFilter applies a saved filter to l. The bubbles list has no
+// setter for its filter, so this replays the keys a user would type. The
+// cursor blink is switched off meanwhile so every returned command finishes
+// immediately.
+func restoreListFilter(l list.Model, text string) list.Model {
+	if text == "" || len(l.Items()) == 0 {
+		return l
+	}
+	mode := l.FilterInput.CursorMode()
+	l.FilterInput.SetCursorMode(textinput.CursorStatic)
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("/")},
+		{Type: tea.KeyRunes, Runes: []rune(text)},
+		{Type: tea.KeyEnter},
+	} {
+		var cmd tea.Cmd
+		l, cmd = l.Update(msg)
+		l = runFilterCmd(l, cmd)
+	}
+	l.FilterInput.SetCursorMode(mode)
+	if l.FilterState() != list.FilterApplied {
+		l.ResetFilter()
+	}
+	return l
+}
+
+// restoreUIState returns to where the last session left off. Recent fonts
+// and the sort mode are already part of the font list; see newModel.
+func (m *model) restoreUIState(state uiState) {
+	m.fontList = restoreListFilter(m.fontList, state.FontFilter)
+	m.pendingFontSelection = state.FontSelected
+	if state.MenuItem != "" {
+		m.jumpToMenuEntry(state.MenuItem)
+	}
+}
+
+func (m model) currentUIState() uiState {
+	state := uiState{RecentFonts: m.recentFonts, FontSort: fontSortNames[m.fontSort]}
+	if entry, ok := m.menu.SelectedItem().(menuEntry); ok {
+		state.MenuItem = entry.id
+	}
+	if m.fontList.FilterState() == list.FilterApplied {
+		state.FontFilter = m.fontList.FilterValue()
+	}
+	if entry, ok := m.fontList.SelectedItem().(fontEntry); ok {
+		state.FontSelected = entry.name
+	}
+	if m.pendingFontSelection != "" {
+		state.FontSelected = m.pendingFontSelection
+	}
+	return state
+}
+
+// saveUIState is best effort, like the draft.
+func (m *model) saveUIState() {
+	if m.payload.ConfigPath == "" {
+		return
+	}
+	_ = m.writePolicy.writeJSONFile(uiStatePath(m.payload.ConfigPath), m.currentUIState())
+}
+
+// openFontPicker selects the font the last session left selected the first
+// time the picker opens, and the configured font after that.
+func (m *model) openFontPicker() {
+	if m.pendingFontSelection != "" {
+		m.selectFontItem(m.pendingFontSelection)
+		m.pendingFontSelection = ""
+	} else {
+		m.selectFontItem(m.payload.Config.Font)
+	}
+	m.screen = screenFontPicker
+}
+
+func (m *model) chooseFont(font string) {
+	m.payload.Config.Font = font
+	m.recentFonts = addRecentFont(m.recentFonts, font)
+	m.refreshFontItems()
+}
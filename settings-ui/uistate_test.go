@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAddRecentFontMovesToFrontAndCaps(t *testing.T) {
+	recent := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	got := addRecentFont(recent, "C")
+	if !reflect.DeepEqual(got, []string{"C", "A", "B", "D", "E", "F", "G", "H"}) {
+		t.Fatalf("unexpected order %v", got)
+	}
+	got = addRecentFont(recent, "New")
+	if len(got) != maxRecentFonts || got[0] != "New" || got[len(got)-1] != "G" {
+		t.Fatalf("expected oldest font dropped, got %v", got)
+	}
+}
+
+func TestRestoreListFilterAppliesFilter(t *testing.T) {
+	var items []list.Item
+	for _, font := range []string{"Big", "Slant", "Small", "Standard"} {
+		items = append(items, fontEntry{name: font})
+	}
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.SetSize(80, 20)
+	l = restoreListFilter(l, "sla")
+	if l.FilterState() != list.FilterApplied || l.FilterValue() != "sla" {
+		t.Fatalf("expected applied filter, got state %v value %q", l.FilterState(), l.FilterValue())
+	}
+	if visible := l.VisibleItems(); len(visible) != 1 || visible[0].(fontEntry).name != "Slant" {
+		t.Fatalf("expected only Slant visible, got %v", visible)
+	}
+
+	l = restoreListFilter(l, "zzz")
+	if len(l.VisibleItems()) == 0 {
+		t.Fatalf("expected a filter matching nothing to be dropped")
+	}
+}
+
+func TestUIStateRoundTrip(t *testing.T) {
+	payload := testPayload()
+	payload.Fonts = []string{"Big", "Slant", "Small", "Standard"}
+	payload.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	state := uiState{MenuItem: "notify", FontFilter: "s", FontSelected: "Small", RecentFonts: []string{"Slant"}}
+	text, _ := json.Marshal(state)
+	if err := os.WriteFile(uiStatePath(payload.ConfigPath), text, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newModel(payload)
+	m.writePolicy = configWritePolicy{dir: filepath.Dir(payload.ConfigPath)}
+	if entry := m.menu.SelectedItem().(menuEntry); entry.id != "notify" || m.section != sectionAlerts {
+		t.Fatalf("expected last menu item restored, got %q in section %v", entry.id, m.section)
+	}
+	if m.fontList.FilterValue() != "s" {
+		t.Fatalf("expected font filter restored, got %q", m.fontList.FilterValue())
+	}
+
+	m.jumpToMenuEntry("font")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next := updated.(model)
+	selected := next.fontList.SelectedItem().(fontEntry)
+	if next.screen != screenFontPicker || selected.name != "Small" {
+		t.Fatalf("expected font picker on Small, got %q", selected.name)
+	}
+	for _, item := range next.fontList.Items() {
+		if entry := item.(fontEntry); entry.recent != (entry.name == "Slant") {
+			t.Fatalf("expected only Slant marked recent, got %+v", entry)
+		}
+	}
+
+	updated, _ = next.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.(model).Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !updated.(model).cancelled {
+		t.Fatalf("expected cancel")
+	}
+
+	saved := loadUIState(uiStatePath(payload.ConfigPath))
+	if saved.MenuItem != "font" || saved.FontFilter != "s" || saved.FontSelected != "Small" {
+		t.Fatalf("unexpected saved state %+v", saved)
+	}
+	if !reflect.DeepEqual(saved.RecentFonts, []string{"Small", "Slant"}) {
+		t.Fatalf("expected chosen font first in recents, got %v", saved.RecentFonts)
+	}
+}
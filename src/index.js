@@ -7,9 +7,12 @@ const figlet = require("figlet");
 
 const PROJECT_ROOT = path.join(__dirname, "..");
 const PREBUILT_SETTINGS_UI_DIR = path.join(PROJECT_ROOT, "settings-ui", "prebuilt");
-const CONFIG_DIR = path.join(os.homedir(), ".cli-timer");
+const CONFIG_DIR = resolveConfigDir();
 const CONFIG_PATH = path.join(CONFIG_DIR, "config.json");
-const SETTINGS_STATE_PATH = path.join(CONFIG_DIR, "settings-state.json");
+const HISTORY_PATH = path.join(CONFIG_DIR, "history.jsonl");
+const POLICY_PATH = process.platform === "win32"
+  ? path.join(process.env.ProgramData || "C:\\ProgramData", "cli-timer", "policy.json")
+  : "/etc/cli-timer/policy.json";
 const DEFAULT_FONT = "Standard";
 const TIMER_SAMPLE_TEXT = "01:23:45";
 const MIN_FIGLET_WIDTH = 120;
@@ -32,6 +35,7 @@ const TIME_CHAR_FALLBACKS = Object.freeze({
 });
 const SYNTHETIC_FILL_CHARS = Object.freeze(["#", "@", "%", "&", "*", "+", "=", "~", "^", "$", "?"]);
 
+const SETTINGS_PROTOCOL_VERSION = 1;
 const MIN_TICK_RATE_MS = 50;
 const MAX_TICK_RATE_MS = 1000;
 const MAC_NOTIFICATION_VERIFY_ATTEMPTS = 8;
@@ -43,7 +47,9 @@ const DEFAULT_KEYBINDINGS = Object.freeze({
   restartKey: "r",
   styleKey: "f",
   exitKey: "q",
-  exitAltKey: "e"
+  exitAltKey: "e",
+  lapKey: "l",
+  deleteKey: "d"
 });
 
 const LEGACY_DEFAULT_KEYBINDINGS = Object.freeze({
@@ -55,6 +61,14 @@ const LEGACY_DEFAULT_KEYBINDINGS = Object.freeze({
   exitAltKey: "e"
 });
 
+const DEFAULT_SETTINGS_KEYS = Object.freeze({
+  save: Object.freeze(["ctrl+s", "ctrl+o"]),
+  back: Object.freeze(["esc", "q"]),
+  quit: Object.freeze(["ctrl+c", "q"]),
+  help: Object.freeze(["?", "f1"]),
+  reset: Object.freeze(["r", "delete"])
+});
+
 const DEFAULT_CONFIG = Object.freeze({
   font: DEFAULT_FONT,
   centerDisplay: true,
@@ -64,7 +78,32 @@ const DEFAULT_CONFIG = Object.freeze({
   completionMessage: "Time is up!",
   notifyOnComplete: true,
   playSoundOnComplete: false,
-  keybindings: { ...DEFAULT_KEYBINDINGS }
+  keybindings: { ...DEFAULT_KEYBINDINGS },
+  settingsKeys: {
+    save: [...DEFAULT_SETTINGS_KEYS.save],
+    back: [...DEFAULT_SETTINGS_KEYS.back],
+    quit: [...DEFAULT_SETTINGS_KEYS.quit],
+    help: [...DEFAULT_SETTINGS_KEYS.help],
+    reset: [...DEFAULT_SETTINGS_KEYS.reset]
+  },
+  favoriteFonts: [],
+  randomFromFavorites: false,
+  timeZone: "",
+  dayFormat: "days",
+  pomodoro: Object.freeze({
+    focusMinutes: 25,
+    shortBreakMinutes: 5,
+    longBreakMinutes: 15,
+    cyclesBeforeLongBreak: 4,
+    autoStartBreaks: true,
+    autoStartFocus: false,
+    focusMessage: "Focus done. Time for a break.",
+    shortBreakMessage: "Break over. Back to focus.",
+    longBreakMessage: "Long break over. Ready for the next set?"
+  }),
+  planDir: "",
+  presets: [],
+  historyRetentionDays: 365
 });
 
 let allFontsCache = null;
@@ -116,6 +155,14 @@ function formatHms(totalSeconds) {
   return [hours, minutes, seconds].map((n) => String(n).padStart(2, "0")).join(":");
 }
 
+// Past 24 hours the days are split off, unless the config asks for hours.
+function formatClock(totalSeconds, dayFormat) {
+  if (dayFormat === "hours" || totalSeconds < 86400) {
+    return formatHms(totalSeconds);
+  }
+  return `${Math.floor(totalSeconds / 86400)}d ${formatHms(totalSeconds % 86400)}`;
+}
+
 function getAllFonts() {
   if (allFontsCache) {
     return allFontsCache;
@@ -130,6 +177,21 @@ function getAllFonts() {
   return allFontsCache;
 }
 
+function resolveFigletFontDir() {
+  try {
+    let dir = path.dirname(require.resolve("figlet"));
+    for (let depth = 0; depth < 4; depth += 1) {
+      const candidate = path.join(dir, "fonts");
+      if (fs.existsSync(path.join(candidate, `${DEFAULT_FONT}.flf`))) {
+        return candidate;
+      }
+      dir = path.dirname(dir);
+    }
+  } catch (_error) {
+  }
+  return "";
+}
+
 function hasVisibleGlyphs(text) {
   return typeof text === "string" && /[^\s]/.test(text);
 }
@@ -326,6 +388,37 @@ function normalizeCompletionMessage(raw) {
   return raw.replace(/\r/g, "").replace(/\n/g, " ").slice(0, 240);
 }
 
+function clampWholeNumber(raw, min, max, fallback) {
+  if (!Number.isFinite(raw) || raw === 0) {
+    return fallback;
+  }
+  return Math.min(max, Math.max(min, Math.floor(raw)));
+}
+
+function normalizePomodoro(raw) {
+  const defaults = DEFAULT_CONFIG.pomodoro;
+  const next = { ...defaults };
+  if (!raw || typeof raw !== "object") {
+    return next;
+  }
+
+  for (const field of ["focusMinutes", "shortBreakMinutes", "longBreakMinutes"]) {
+    next[field] = clampWholeNumber(raw[field], 1, 180, defaults[field]);
+  }
+  next.cyclesBeforeLongBreak = clampWholeNumber(raw.cyclesBeforeLongBreak, 1, 12, defaults.cyclesBeforeLongBreak);
+  for (const field of ["autoStartBreaks", "autoStartFocus"]) {
+    if (typeof raw[field] === "boolean") {
+      next[field] = raw[field];
+    }
+  }
+  for (const field of ["focusMessage", "shortBreakMessage", "longBreakMessage"]) {
+    if (typeof raw[field] === "string" && raw[field] !== "") {
+      next[field] = normalizeCompletionMessage(raw[field]);
+    }
+  }
+  return next;
+}
+
 function normalizeKeyToken(raw, fallback) {
   if (typeof raw !== "string") {
     return fallback;
@@ -355,6 +448,8 @@ function normalizeKeybindings(raw) {
   next.styleKey = normalizeKeyToken(raw.styleKey, next.styleKey);
   next.exitKey = normalizeKeyToken(raw.exitKey, next.exitKey);
   next.exitAltKey = normalizeKeyToken(raw.exitAltKey, next.exitAltKey);
+  next.lapKey = normalizeKeyToken(raw.lapKey, next.lapKey);
+  next.deleteKey = normalizeKeyToken(raw.deleteKey, next.deleteKey);
 
   if (
     next.pauseKey === LEGACY_DEFAULT_KEYBINDINGS.pauseKey &&
@@ -364,15 +459,62 @@ function normalizeKeybindings(raw) {
     next.exitKey === LEGACY_DEFAULT_KEYBINDINGS.exitKey &&
     next.exitAltKey === LEGACY_DEFAULT_KEYBINDINGS.exitAltKey
   ) {
-    return { ...DEFAULT_KEYBINDINGS };
+    return { ...DEFAULT_KEYBINDINGS, lapKey: next.lapKey, deleteKey: next.deleteKey };
   }
 
   return next;
 }
 
+function normalizeSettingsKeyList(raw, fallback) {
+  const next = [];
+  if (Array.isArray(raw)) {
+    for (const item of raw) {
+      if (typeof item !== "string") {
+        continue;
+      }
+      const value = item.trim().toLowerCase();
+      if (!value || value.length > 16 || /\s/.test(value) || value === "enter" || value === "/") {
+        continue;
+      }
+      if (!next.includes(value)) {
+        next.push(value);
+      }
+    }
+  }
+  return next.length > 0 ? next : [...fallback];
+}
+
+function normalizeSettingsKeys(raw) {
+  const source = raw && typeof raw === "object" ? raw : {};
+  return {
+    save: normalizeSettingsKeyList(source.save, DEFAULT_SETTINGS_KEYS.save),
+    back: normalizeSettingsKeyList(source.back, DEFAULT_SETTINGS_KEYS.back),
+    quit: normalizeSettingsKeyList(source.quit, DEFAULT_SETTINGS_KEYS.quit),
+    help: normalizeSettingsKeyList(source.help, DEFAULT_SETTINGS_KEYS.help),
+    reset: normalizeSettingsKeyList(source.reset, DEFAULT_SETTINGS_KEYS.reset)
+  };
+}
+
+// Keep in sync with resolveConfigDir in settings-ui/paths.go: an existing
+// ~/.cli-timer always wins; otherwise an absolute XDG_CONFIG_HOME is honored.
+function resolveConfigDir() {
+  const legacy = path.join(os.homedir(), ".cli-timer");
+  try {
+    if (fs.statSync(legacy).isDirectory()) {
+      return legacy;
+    }
+  } catch (_error) {
+  }
+  const xdg = process.env.XDG_CONFIG_HOME;
+  if (xdg && path.isAbsolute(xdg)) {
+    return path.join(xdg, "cli-timer");
+  }
+  return legacy;
+}
+
 function ensureConfigDir() {
   if (!fs.existsSync(CONFIG_DIR)) {
-    fs.mkdirSync(CONFIG_DIR, { recursive: true });
+    fs.mkdirSync(CONFIG_DIR, { recursive: true, mode: 0o700 });
   }
 }
 
@@ -386,7 +528,16 @@ function normalizeConfig(raw) {
     completionMessage: DEFAULT_CONFIG.completionMessage,
     notifyOnComplete: DEFAULT_CONFIG.notifyOnComplete,
     playSoundOnComplete: DEFAULT_CONFIG.playSoundOnComplete,
-    keybindings: { ...DEFAULT_KEYBINDINGS }
+    keybindings: { ...DEFAULT_KEYBINDINGS },
+    settingsKeys: normalizeSettingsKeys(null),
+    favoriteFonts: [],
+    randomFromFavorites: DEFAULT_CONFIG.randomFromFavorites,
+    timeZone: DEFAULT_CONFIG.timeZone,
+    dayFormat: DEFAULT_CONFIG.dayFormat,
+    pomodoro: normalizePomodoro(null),
+    planDir: DEFAULT_CONFIG.planDir,
+    presets: [],
+    historyRetentionDays: DEFAULT_CONFIG.historyRetentionDays
   };
 
   if (raw && typeof raw === "object") {
@@ -412,6 +563,23 @@ function normalizeConfig(raw) {
       next.playSoundOnComplete = raw.playSoundOnComplete;
     }
     next.keybindings = normalizeKeybindings(raw.keybindings);
+    next.settingsKeys = normalizeSettingsKeys(raw.settingsKeys);
+    next.favoriteFonts = normalizeFavoriteFonts(raw.favoriteFonts);
+    if (typeof raw.randomFromFavorites === "boolean") {
+      next.randomFromFavorites = raw.randomFromFavorites;
+    }
+    if (typeof raw.timeZone === "string" && isValidTimeZone(raw.timeZone.trim())) {
+      next.timeZone = raw.timeZone.trim();
+    }
+    if (raw.dayFormat === "hours") {
+      next.dayFormat = "hours";
+    }
+    next.pomodoro = normalizePomodoro(raw.pomodoro);
+    if (typeof raw.planDir === "string") {
+      next.planDir = raw.planDir.trim();
+    }
+    next.presets = normalizePresets(raw.presets);
+    next.historyRetentionDays = clampWholeNumber(raw.historyRetentionDays, 1, 3650, DEFAULT_CONFIG.historyRetentionDays);
     if (typeof raw.font === "string") {
       const normalizedFont = normalizeFontName(raw.font);
       if (normalizedFont) {
@@ -423,23 +591,185 @@ function normalizeConfig(raw) {
   return next;
 }
 
+function isValidTimeZone(name) {
+  if (name === "") {
+    return true;
+  }
+  try {
+    new Intl.DateTimeFormat("en-US", { timeZone: name });
+    return true;
+  } catch (_error) {
+    return false;
+  }
+}
+
+function normalizeFavoriteFonts(value) {
+  if (!Array.isArray(value)) {
+    return [];
+  }
+  const result = [];
+  for (const item of value) {
+    const font = typeof item === "string" ? normalizeFontName(item) : null;
+    if (font && !result.includes(font)) {
+      result.push(font);
+    }
+  }
+  return result;
+}
+
+// Durations are checked by the Go runtime, which runs `timer preset`; this
+// only keeps well-formed entries so writeConfig does not drop them.
+function normalizePresets(value) {
+  if (!Array.isArray(value)) {
+    return [];
+  }
+  const result = [];
+  for (const item of value) {
+    if (!item || typeof item.name !== "string" || typeof item.duration !== "string") {
+      continue;
+    }
+    const name = item.name.trim();
+    if (!name || result.some((preset) => preset.name.toLowerCase() === name.toLowerCase())) {
+      continue;
+    }
+    const preset = { name, duration: item.duration.trim() };
+    if (typeof item.font === "string" && item.font.trim()) {
+      preset.font = item.font.trim();
+    }
+    if (typeof item.message === "string" && item.message) {
+      preset.message = normalizeCompletionMessage(item.message);
+    }
+    result.push(preset);
+  }
+  return result;
+}
+
+// Fonts the random style key and `timer style random` pick from: the starred
+// favorites when randomFromFavorites is on and any are installed.
+function getRandomStyleFonts(config) {
+  const fonts = getAllFonts();
+  if (config.randomFromFavorites) {
+    const favorites = config.favoriteFonts.filter((font) => fonts.includes(font));
+    if (favorites.length > 0) {
+      return favorites;
+    }
+  }
+  return fonts;
+}
+
+function flattenPolicy(prefix, value, out) {
+  if (!value || typeof value !== "object" || Array.isArray(value)) {
+    out[prefix] = value;
+    return out;
+  }
+  for (const [key, child] of Object.entries(value)) {
+    flattenPolicy(prefix ? `${prefix}.${key}` : key, child, out);
+  }
+  return out;
+}
+
+// loadPolicy parses the policy file like loadAdminPolicy in
+// settings-ui/policy.go: unknown settings and values of the wrong type are
+// errors, not skipped.
+function loadPolicy() {
+  if (!fs.existsSync(POLICY_PATH)) {
+    return {};
+  }
+  let parsed;
+  try {
+    parsed = JSON.parse(fs.readFileSync(POLICY_PATH, "utf8"));
+  } catch (error) {
+    throw new Error(`policy ${POLICY_PATH}: ${error.message}`);
+  }
+  if (parsed === null) {
+    return {};
+  }
+  if (typeof parsed !== "object" || Array.isArray(parsed)) {
+    throw new Error(`policy ${POLICY_PATH}: expected a JSON object`);
+  }
+  if (parsed.locked === undefined || parsed.locked === null) {
+    return {};
+  }
+  if (typeof parsed.locked !== "object" || Array.isArray(parsed.locked)) {
+    throw new Error(`policy ${POLICY_PATH}: "locked" must be an object`);
+  }
+
+  const locked = flattenPolicy("", parsed.locked, {});
+  const known = flattenPolicy("", DEFAULT_CONFIG, {});
+  for (const [name, value] of Object.entries(locked)) {
+    if (!Object.prototype.hasOwnProperty.call(known, name)) {
+      throw new Error(`policy ${POLICY_PATH}: unknown setting "${name}"`);
+    }
+    const expected = known[name];
+    const mismatched =
+      value !== null &&
+      (Array.isArray(value) !== Array.isArray(expected) ||
+        typeof value !== typeof expected ||
+        (Number.isInteger(expected) && !Number.isInteger(value)));
+    if (mismatched) {
+      throw new Error(`policy ${POLICY_PATH}: cannot set "${name}" to ${JSON.stringify(value)}`);
+    }
+  }
+  return locked;
+}
+
+// Admin policy (see settings-ui/policy.go): {"locked": {...}} pins config
+// fields, keyed by dotted path, to fixed values. A broken policy stops the
+// command instead of silently unlocking everything.
+let cachedPolicy = null;
+function readPolicy() {
+  if (cachedPolicy) {
+    return cachedPolicy;
+  }
+  try {
+    cachedPolicy = loadPolicy();
+  } catch (error) {
+    process.stderr.write(`Failed to load policy: ${error.message}\n`);
+    process.exit(1);
+  }
+  return cachedPolicy;
+}
+
+function isSettingLocked(name) {
+  return Object.prototype.hasOwnProperty.call(readPolicy(), name);
+}
+
+function applyPolicy(config) {
+  const next = { ...config, keybindings: { ...config.keybindings }, settingsKeys: { ...config.settingsKeys } };
+  for (const [name, value] of Object.entries(readPolicy())) {
+    const parts = name.split(".");
+    let target = next;
+    for (const part of parts.slice(0, -1)) {
+      if (!target[part] || typeof target[part] !== "object") {
+        target = null;
+        break;
+      }
+      target = target[part];
+    }
+    if (target) {
+      target[parts[parts.length - 1]] = value;
+    }
+  }
+  return normalizeConfig(next);
+}
+
 function readConfig() {
   try {
     if (!fs.existsSync(CONFIG_PATH)) {
-      return normalizeConfig({});
+      return applyPolicy(normalizeConfig({}));
     }
     const text = fs.readFileSync(CONFIG_PATH, "utf8");
     const parsed = JSON.parse(text);
-    return normalizeConfig(parsed);
+    return applyPolicy(normalizeConfig(parsed));
   } catch (_error) {
-    return normalizeConfig({});
+    return applyPolicy(normalizeConfig({}));
   }
 }
 
 function writeConfig(config) {
   ensureConfigDir();
   const normalized = normalizeConfig(config);
-  fs.writeFileSync(CONFIG_PATH, `${JSON.stringify(normalized, null, 2)}\n`, "utf8");
+  fs.writeFileSync(CONFIG_PATH, `${JSON.stringify(normalized, null, 2)}\n`, { encoding: "utf8", mode: 0o600 });
 }
 
 function updateConfig(patch) {
@@ -454,6 +784,9 @@ function getFontFromConfig() {
 }
 
 function setFontInConfig(requestedFont) {
+  if (isSettingLocked("font")) {
+    return { ok: false, reason: "locked", font: readConfig().font };
+  }
   const normalized = normalizeFontName(requestedFont);
   if (!normalized) {
     return { ok: false, reason: "unknown", font: null };
@@ -493,6 +826,54 @@ function pickRandomFont(fonts, currentFont) {
   return fonts[(currentIndex + offset) % fonts.length];
 }
 
+// Mirrors extractSessionTags in settings-ui/cmdargs.go: --label names a
+// timer or stopwatch, --tag (repeatable, comma-separated) groups it in
+// `timer report`.
+function extractSessionFlags(args) {
+  const labels = [];
+  const tagValues = [];
+  const rest = [];
+  for (let index = 0; index < args.length; index += 1) {
+    const arg = args[index];
+    const match = /^--?(label|tag)(?:=(.*))?$/.exec(arg);
+    if (!match) {
+      rest.push(arg);
+      continue;
+    }
+    let value = match[2];
+    if (value === undefined) {
+      if (index + 1 >= args.length) {
+        return { ok: false, error: `--${match[1]} needs a value` };
+      }
+      index += 1;
+      value = args[index];
+    }
+    (match[1] === "label" ? labels : tagValues).push(value);
+  }
+
+  const label = labels.length > 0 ? labels[labels.length - 1].trim().split(/\s+/).filter(Boolean).join(" ") : "";
+  if ([...label].length > 60) {
+    return { ok: false, error: "--label is longer than 60 characters" };
+  }
+  const tags = [];
+  for (const value of tagValues) {
+    for (const raw of value.split(",")) {
+      const tag = raw.trim();
+      if (tag === "" || tags.some((existing) => existing.toLowerCase() === tag.toLowerCase())) {
+        continue;
+      }
+      if ([...tag].length > 32) {
+        return { ok: false, error: `tag "${tag}" is longer than 32 characters` };
+      }
+      tags.push(tag);
+    }
+  }
+  if (tags.length > 10) {
+    return { ok: false, error: "at most 10 tags can be given" };
+  }
+  return { ok: true, label, tags, rest };
+}
+
 function parseDurationArgs(args) {
   if (args.length === 0 || args.length % 2 !== 0) {
     return { ok: false, error: "Duration must be in <number> <unit> pairs." };
@@ -651,12 +1032,12 @@ function writeCenteredBlockWithTop(topLines, centerLines) {
   writeFrameLines(toDisplayLines(output));
 }
 
-function drawFrame({ mode, seconds, paused, config, done }) {
+function drawFrame({ mode, label, seconds, paused, config, done }) {
   clearScreen();
 
   const topLines = [];
   const centerLines = [];
-  const title = mode === "timer" ? "Timer" : "Stopwatch";
+  const title = label || (mode === "timer" ? "Timer" : "Stopwatch");
 
   if (config.showHeader) {
     topLines.push(`${title} | Font: ${config.font}`);
@@ -668,7 +1049,7 @@ function drawFrame({ mode, seconds, paused, config, done }) {
     topLines.push("");
   }
 
-  centerLines.push(...toDisplayLines(renderTimeAscii(formatHms(seconds), config.font)));
+  centerLines.push(...toDisplayLines(renderTimeAscii(formatClock(seconds, config.dayFormat), config.font)));
 
   if (done) {
     if (config.completionMessage) {
@@ -1068,22 +1449,102 @@ function playCompletionAlarm(config) {
   }
 }
 
-function notifyTimerFinished(config, initialSeconds) {
+function notifyTimerFinished(config, initialSeconds, label) {
   if (!config) {
     return;
   }
 
   if (config.notifyOnComplete) {
     const message = config.completionMessage || "Time is up!";
-    const title = initialSeconds ? `Timer finished (${formatHms(initialSeconds)})` : "Timer finished";
+    const name = label || "Timer";
+    const title = initialSeconds ? `${name} finished (${formatHms(initialSeconds)})` : `${name} finished`;
     sendSystemNotification({ title, message });
   }
 
   playCompletionAlarm(config);
 }
 
-function runNonInteractiveTimer(initialSeconds, tickRateMs) {
+function historyTimestamp(ms) {
+  return new Date(Math.floor(ms / 1000) * 1000).toISOString().replace(".000Z", "Z");
+}
+
+// Mirrors historyEntry in settings-ui/history.go: one JSON line per session.
+function buildHistoryEntry({ mode, label, tags, startMs, endMs, plannedSeconds, pausedMs, pauses, completed }) {
+  const entry = { mode };
+  if (label) {
+    entry.label = label;
+  }
+  if (tags && tags.length > 0) {
+    entry.tags = tags;
+  }
+  entry.start = historyTimestamp(startMs);
+  entry.end = historyTimestamp(endMs);
+  entry.plannedSeconds = plannedSeconds;
+  entry.actualSeconds = Math.max(0, Math.floor((endMs - startMs - pausedMs) / 1000));
+  entry.pausedSeconds = Math.floor(pausedMs / 1000);
+  entry.pauses = pauses;
+  entry.completed = completed;
+  return entry;
+}
+
+function readHistoryEntries() {
+  if (!fs.existsSync(HISTORY_PATH)) {
+    return [];
+  }
+  const entries = [];
+  for (const line of fs.readFileSync(HISTORY_PATH, "utf8").split("\n")) {
+    try {
+      const entry = JSON.parse(line);
+      if (entry && typeof entry.start === "string" && !Number.isNaN(Date.parse(entry.start))) {
+        entries.push(entry);
+      }
+    } catch (_error) {
+      // Broken lines are skipped, as the Go side does.
+    }
+  }
+  return entries;
+}
+
+// Appends sessions to history.jsonl, dropping entries older than the
+// retention window the same way appendHistory in history.go does.
+function appendHistory(entries, retentionDays) {
+  const existing = readHistoryEntries();
+  const cutoff = new Date();
+  cutoff.setDate(cutoff.getDate() - retentionDays);
+  const kept = existing.filter((entry) => !(Date.parse(entry.end) < cutoff.getTime()));
+  const lines = (list) => list.map((entry) => `${JSON.stringify(entry)}\n`).join("");
+
+  ensureConfigDir();
+  if (kept.length === existing.length) {
+    fs.appendFileSync(HISTORY_PATH, lines(entries), { encoding: "utf8", mode: 0o600 });
+    return;
+  }
+  const tempPath = path.join(CONFIG_DIR, `.history-${process.pid}-${Date.now()}.jsonl`);
+  try {
+    fs.writeFileSync(tempPath, lines(kept.concat(entries)), { encoding: "utf8", mode: 0o600 });
+    fs.renameSync(tempPath, HISTORY_PATH);
+  } finally {
+    if (fs.existsSync(tempPath)) {
+      fs.unlinkSync(tempPath);
+    }
+  }
+}
+
+function recordSessions(config, entries) {
+  const ran = entries.filter((entry) => entry.actualSeconds > 0 || (entry.completed && entry.plannedSeconds > 0));
+  if (ran.length === 0) {
+    return;
+  }
+  try {
+    appendHistory(ran, config.historyRetentionDays);
+  } catch (error) {
+    process.stderr.write(`Failed to write history: ${error.message}\n`);
+  }
+}
+
+function runNonInteractiveTimer(initialSeconds, tickRateMs, { label, tags }) {
   const startedAt = Date.now();
+  const { dayFormat } = readConfig();
   let lastSecond = null;
   let notified = false;
 
@@ -1094,7 +1555,7 @@ function runNonInteractiveTimer(initialSeconds, tickRateMs) {
       return remaining;
     }
     lastSecond = remaining;
-    process.stdout.write(`${formatHms(remaining)}\n`);
+    process.stdout.write(`${formatClock(remaining, dayFormat)}\n`);
     return remaining;
   }
 
@@ -1108,13 +1569,28 @@ function runNonInteractiveTimer(initialSeconds, tickRateMs) {
       clearInterval(interval);
       if (!notified) {
         notified = true;
-        notifyTimerFinished(readConfig(), initialSeconds);
+        const config = readConfig();
+        notifyTimerFinished(config, initialSeconds, label);
+        const endMs = Date.now();
+        recordSessions(config, [
+          buildHistoryEntry({
+            mode: "timer",
+            label,
+            tags,
+            startMs: startedAt,
+            endMs,
+            plannedSeconds: initialSeconds,
+            pausedMs: 0,
+            pauses: 0,
+            completed: true
+          })
+        ]);
       }
     }
   }, tickRateMs);
 }
 
-function runClock({ mode, initialSeconds, config }) {
+function runClock({ mode, initialSeconds, config, label = "", tags = [] }) {
   const isTimer = mode === "timer";
   const tickRateMs = sanitizeTickRate(config.tickRateMs);
 
@@ -1124,7 +1600,7 @@ function runClock({ mode, initialSeconds, config }) {
       process.exitCode = 1;
       return;
     }
-    runNonInteractiveTimer(initialSeconds, tickRateMs);
+    runNonInteractiveTimer(initialSeconds, tickRateMs, { label, tags });
     return;
   }
 
@@ -1140,6 +1616,12 @@ function runClock({ mode, initialSeconds, config }) {
   let didEnterAlternateScreen = false;
   let didDisableLineWrap = false;
   let didEnableMouseCapture = false;
+  // Session bookkeeping for history.jsonl; a restart stays the same session.
+  const sessionStartMs = Date.now();
+  let sessionPausedMs = 0;
+  let sessionPausedAtMs = null;
+  let sessionPauses = 0;
+  let doneAtMs = null;
 
   const stdin = process.stdin;
 
@@ -1160,9 +1642,10 @@ function runClock({ mode, initialSeconds, config }) {
     if (isTimer && displaySeconds <= 0 && !done) {
       done = true;
       paused = true;
+      doneAtMs = Date.now();
       if (!didNotifyCompletion) {
         didNotifyCompletion = true;
-        notifyTimerFinished(config, baseSeconds);
+        notifyTimerFinished(config, baseSeconds, label);
       }
     }
   }
@@ -1177,6 +1660,7 @@ function runClock({ mode, initialSeconds, config }) {
     lastDrawState = stateKey;
     drawFrame({
       mode,
+      label,
       seconds: displaySeconds,
       paused,
       config,
@@ -1184,7 +1668,32 @@ function runClock({ mode, initialSeconds, config }) {
     });
   }
 
+  function resumeSession() {
+    if (sessionPausedAtMs !== null) {
+      sessionPausedMs += Date.now() - sessionPausedAtMs;
+      sessionPausedAtMs = null;
+    }
+  }
+
+  function sessionHistoryEntry() {
+    const endMs = done && doneAtMs !== null ? doneAtMs : Date.now();
+    const pausedMs = sessionPausedMs + (sessionPausedAtMs !== null ? Math.max(0, endMs - sessionPausedAtMs) : 0);
+    return buildHistoryEntry({
+      mode,
+      label,
+      tags,
+      startMs: sessionStartMs,
+      endMs,
+      plannedSeconds: isTimer ? baseSeconds : 0,
+      pausedMs,
+      pauses: sessionPauses,
+      completed: done || !isTimer
+    });
+  }
+
   function restart() {
+    resumeSession();
+    doneAtMs = null;
     paused = false;
     done = false;
     didNotifyCompletion = false;
@@ -1195,7 +1704,7 @@ function runClock({ mode, initialSeconds, config }) {
   }
 
   function cycleStyle() {
-    const fonts = getAllFonts();
+    const fonts = getRandomStyleFonts(config);
     if (fonts.length === 0) {
       return;
     }
@@ -1204,6 +1713,9 @@ function runClock({ mode, initialSeconds, config }) {
       return;
     }
     const updated = setFontInConfig(nextFont);
+    if (updated.reason === "locked") {
+      return;
+    }
     config.font = updated.ok ? updated.font : nextFont;
     lastDrawState = "";
     draw(true);
@@ -1246,6 +1758,7 @@ function runClock({ mode, initialSeconds, config }) {
     } else {
       clearScreen();
     }
+    recordSessions(config, [sessionHistoryEntry()]);
     process.exit(code);
   }
 
@@ -1253,10 +1766,13 @@ function runClock({ mode, initialSeconds, config }) {
     if (paused) {
       paused = false;
       anchorMs = Date.now();
+      resumeSession();
       return;
     }
     paused = true;
     elapsedWhilePaused += Date.now() - anchorMs;
+    sessionPausedAtMs = Date.now();
+    sessionPauses += 1;
   }
 
   function onKeypress(chunk) {
@@ -1316,6 +1832,80 @@ function runClock({ mode, initialSeconds, config }) {
   tick = setInterval(() => draw(false), tickRateMs);
 }
 
+const platformMap = {
+  linux: "linux",
+  darwin: "darwin",
+  win32: "windows"
+};
+const archMap = {
+  x64: "x64",
+  arm64: "arm64"
+};
+
+function getSettingsBinaryTarget() {
+  const platform = platformMap[process.platform];
+  const arch = archMap[process.arch];
+  if (!platform || !arch) {
+    return null;
+  }
+  return `${platform}-${arch}`;
+}
+
+function getPrebuiltSettingsBinaryPath() {
+  const target = getSettingsBinaryTarget();
+  if (!target) {
+    return null;
+  }
+  const binaryName = process.platform === "win32" ? "cli-timer-settings-ui.exe" : "cli-timer-settings-ui";
+  const fullPath = path.join(PREBUILT_SETTINGS_UI_DIR, target, binaryName);
+  if (!fs.existsSync(fullPath)) {
+    return null;
+  }
+  return fullPath;
+}
+
+function hasGoToolchain() {
+  const goVersion = spawnSync("go", ["version"], { stdio: "ignore" });
+  return !(goVersion.error || goVersion.status !== 0);
+}
+
+// Some commands, such as `timer until 17:30`, only exist in the Go runtime.
+// They run through the same binary as `timer settings`, on the same config.
+function runGoRuntime(args) {
+  const goArgs = ["--config-path", CONFIG_PATH];
+  const fontDir = resolveFigletFontDir();
+  if (fontDir) {
+    goArgs.push("--font-dir", fontDir);
+  }
+  goArgs.push(...args);
+
+  let result = null;
+  const prebuiltPath = getPrebuiltSettingsBinaryPath();
+  if (prebuiltPath) {
+    result = spawnSync(prebuiltPath, goArgs, { stdio: "inherit" });
+  }
+  if ((!result || result.error) && hasGoToolchain()) {
+    result = spawnSync("go", ["run", ".", ...goArgs], {
+      cwd: path.join(PROJECT_ROOT, "settings-ui"),
+      stdio: "inherit"
+    });
+  }
+  if (!result) {
+    const command = args[0] === "timer" ? args[1] : args[0];
+    process.stderr.write(`\`timer ${command}\` needs the prebuilt Go runtime or an installed Go toolchain.\n`);
+    process.exitCode = 1;
+    return;
+  }
+  if (result.error) {
+    process.stderr.write(`Failed to run timer: ${result.error.message}\n`);
+    process.exitCode = 1;
+    return;
+  }
+  if (typeof result.status === "number" && result.status !== 0) {
+    process.exitCode = result.status;
+  }
+}
+
 function runSettingsUI() {
   if (!process.stdin.isTTY || !process.stdout.isTTY) {
     process.stderr.write("`timer settings` requires an interactive terminal (TTY).\n");
@@ -1325,62 +1915,68 @@ function runSettingsUI() {
 
   ensureConfigDir();
 
-  const platformMap = {
-    linux: "linux",
-    darwin: "darwin",
-    win32: "windows"
-  };
-  const archMap = {
-    x64: "x64",
-    arm64: "arm64"
+  const state = {
+    configPath: CONFIG_PATH,
+    config: readConfig(),
+    fonts: getAllFonts(),
+    fontDir: resolveFigletFontDir()
   };
 
-  function getSettingsBinaryTarget() {
-    const platform = platformMap[process.platform];
-    const arch = archMap[process.arch];
-    if (!platform || !arch) {
-      return null;
+  function parseProtocolResult(stdout) {
+    try {
+      const parsed = JSON.parse(String(stdout || ""));
+      if (parsed && typeof parsed === "object" && typeof parsed.status === "string") {
+        return parsed;
+      }
+    } catch (_error) {
     }
-    return `${platform}-${arch}`;
+    return null;
   }
 
-  function getPrebuiltSettingsBinaryPath() {
-    const target = getSettingsBinaryTarget();
-    if (!target) {
-      return null;
-    }
-    const binaryName = process.platform === "win32" ? "cli-timer-settings-ui.exe" : "cli-timer-settings-ui";
-    const fullPath = path.join(PREBUILT_SETTINGS_UI_DIR, target, binaryName);
-    if (!fs.existsSync(fullPath)) {
-      return null;
-    }
-    return fullPath;
+  // Go's flag package exits with status 2 and this message on an unknown flag.
+  function rejectsProtocolFlag(result) {
+    return result.status === 2 && /flag provided but not defined: -+protocol\b/.test(String(result.stderr || ""));
   }
 
-  function hasGoToolchain() {
-    const goVersion = spawnSync("go", ["version"], { stdio: "ignore" });
-    return !(goVersion.error || goVersion.status !== 0);
+  // The state payload goes over stdin and the outcome comes back as JSON on
+  // stdout; the UI itself talks to the terminal directly, so stderr only
+  // carries errors and is passed on once the process exits. Only binaries
+  // that predate --protocol, and reject the flag, get the state file instead;
+  // any other failure is reported as is rather than running the UI twice.
+  function runSettingsProcess(command, args, cwd) {
+    const request = JSON.stringify({ protocolVersion: SETTINGS_PROTOCOL_VERSION, ...state });
+    const result = spawnSync(command, [...args, "--protocol"], {
+      cwd,
+      input: request,
+      stdio: ["pipe", "pipe", "pipe"]
+    });
+    if (result.error || !rejectsProtocolFlag(result)) {
+      if (result.stderr && result.stderr.length > 0) {
+        process.stderr.write(result.stderr);
+      }
+      return { ...result, protocol: parseProtocolResult(result.stdout) };
+    }
+
+    // A fresh private directory keeps other users from predicting, swapping
+    // or reading the state file.
+    const stateDir = fs.mkdtempSync(path.join(os.tmpdir(), "cli-timer-settings-"));
+    const statePath = path.join(stateDir, "state.json");
+    try {
+      fs.writeFileSync(statePath, JSON.stringify(state), { encoding: "utf8", mode: 0o600, flag: "wx" });
+      return spawnSync(command, [...args, "--state", statePath], { cwd, stdio: "inherit" });
+    } finally {
+      fs.rmSync(stateDir, { recursive: true, force: true });
+    }
   }
 
   function runPrebuiltSettingsUI(binaryPath) {
-    return spawnSync(binaryPath, ["--state", SETTINGS_STATE_PATH], { stdio: "inherit" });
+    return runSettingsProcess(binaryPath, [], undefined);
   }
 
   function runGoSettingsUI() {
-    return spawnSync("go", ["run", ".", "--state", SETTINGS_STATE_PATH], {
-      cwd: path.join(PROJECT_ROOT, "settings-ui"),
-      stdio: "inherit"
-    });
+    return runSettingsProcess("go", ["run", "."], path.join(PROJECT_ROOT, "settings-ui"));
   }
 
-  const state = {
-    configPath: CONFIG_PATH,
-    config: readConfig(),
-    fonts: getAllFonts()
-  };
-
-  fs.writeFileSync(SETTINGS_STATE_PATH, JSON.stringify(state), "utf8");
-
   let result;
   const prebuiltPath = getPrebuiltSettingsBinaryPath();
   const canRunGo = hasGoToolchain();
@@ -1405,11 +2001,6 @@ function runSettingsUI() {
     process.exitCode = 1;
   }
 
-  try {
-    fs.unlinkSync(SETTINGS_STATE_PATH);
-  } catch (_error) {
-  }
-
   if (!result) {
     return;
   }
@@ -1420,6 +2011,14 @@ function runSettingsUI() {
     return;
   }
 
+  if (result.protocol) {
+    if (result.protocol.status === "error") {
+      process.stderr.write(`Failed to save settings: ${result.protocol.error || "unknown error"}\n`);
+      process.exitCode = 1;
+    }
+    return;
+  }
+
   if (result.status === 2) {
     return;
   }
@@ -1432,10 +2031,31 @@ function runSettingsUI() {
 function printUsage() {
   process.stdout.write("Usage\n\n");
   process.stdout.write("Stopwatch\n");
-  process.stdout.write("  stopwatch\n\n");
+  process.stdout.write("  stopwatch [--label <text>] [--tag <tag>]\n\n");
   process.stdout.write("Timer\n");
   process.stdout.write("  timer <number> <hr/hrs/min/sec> [<number> <hr/hrs/min/sec> ...]\n");
-  process.stdout.write("  Example: timer 5 min 2 sec\n\n");
+  process.stdout.write("  Example: timer 5 min 2 sec\n");
+  process.stdout.write("  timer until <time or date> [--tz <zone>]\n");
+  process.stdout.write("  Example: timer until 2026-12-31T23:59 --tz Europe/Berlin\n");
+  process.stdout.write("  Any timer takes --label <text> (shown in the header) and --tag <tag> (repeatable)\n");
+  process.stdout.write("  Example: timer 45 min --label \"code review\" --tag work\n\n");
+  process.stdout.write("Presets\n");
+  process.stdout.write("  timer preset          list presets\n");
+  process.stdout.write("  timer preset <name>\n");
+  process.stdout.write("  Presets are added and edited in `timer settings`.\n\n");
+  process.stdout.write("Pomodoro\n");
+  process.stdout.write("  timer pomodoro\n");
+  process.stdout.write("  Phase lengths and cycles are set in `timer settings`.\n\n");
+  process.stdout.write("Interval plans\n");
+  process.stdout.write("  timer plan            list plans in the plan directory\n");
+  process.stdout.write("  timer plan <name or file>\n\n");
+  process.stdout.write("Dashboard\n");
+  process.stdout.write("  timer dashboard [--add \"<name> <duration>\"]... [--tag <tag>]\n");
+  process.stdout.write("  Example: timer dashboard --add \"tea 4 min\" --add \"laundry 45 min\" --add build\n");
+  process.stdout.write("  Several timers at once; n adds one, d deletes the selected row.\n\n");
+  process.stdout.write("Reports\n");
+  process.stdout.write("  timer report [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--tag <tag>] [--format table|csv|json] [--output <file>]\n");
+  process.stdout.write("  Time per tag from the session history, this week by default.\n\n");
   process.stdout.write("Settings\n");
   process.stdout.write("  timer settings\n\n");
   process.stdout.write("Update\n");
@@ -1451,9 +2071,23 @@ function printUsage() {
   process.stdout.write("  timer style <font>\n");
 }
 
-function runStopwatch() {
+function runStopwatch(args = []) {
+  // Laps and lap export live in the Go stopwatch; the loop below only runs
+  // when neither the prebuilt binary nor a Go toolchain is available.
+  if (getPrebuiltSettingsBinaryPath() || hasGoToolchain()) {
+    ensureConfigDir();
+    runGoRuntime(["stopwatch", ...args]);
+    return;
+  }
+  const session = extractSessionFlags(args);
+  if (!session.ok || session.rest.length > 0) {
+    process.stderr.write(`${session.ok ? `Unexpected arguments: ${session.rest.join(" ")}` : session.error}\n\n`);
+    printUsage();
+    process.exitCode = 1;
+    return;
+  }
   const config = readConfig();
-  runClock({ mode: "stopwatch", initialSeconds: 0, config });
+  runClock({ mode: "stopwatch", initialSeconds: 0, config, label: session.label, tags: session.tags });
 }
 
 function runTimer(args) {
@@ -1500,7 +2134,7 @@ function runTimer(args) {
     }
 
     if (args.length === 2 && args[1].toLowerCase() === "random") {
-      const fonts = getAllFonts();
+      const fonts = getRandomStyleFonts(readConfig());
       if (fonts.length === 0) {
         process.stderr.write("No fonts are available.\n");
         process.exitCode = 1;
@@ -1513,6 +2147,11 @@ function runTimer(args) {
         return;
       }
       const result = setFontInConfig(randomFont);
+      if (result.reason === "locked") {
+        process.stderr.write("The font is locked by your administrator.\n");
+        process.exitCode = 1;
+        return;
+      }
       if (!result.ok) {
         process.stderr.write(`Failed to set random font: ${randomFont}\n`);
         process.exitCode = 1;
@@ -1524,6 +2163,11 @@ function runTimer(args) {
 
     const requestedFont = args.slice(1).join(" ");
     const result = setFontInConfig(requestedFont);
+    if (result.reason === "locked") {
+      process.stderr.write("The font is locked by your administrator.\n");
+      process.exitCode = 1;
+      return;
+    }
     if (!result.ok) {
       process.stderr.write(`Unknown font: ${requestedFont}\n`);
       process.stderr.write("Run `timer style` to list fonts.\n");
@@ -1534,7 +2178,20 @@ function runTimer(args) {
     return;
   }
 
-  const parsed = parseDurationArgs(args);
+  if (args[0] === "preset" || args[0] === "pomodoro" || args[0] === "plan" || args[0] === "report" || args[0] === "dashboard") {
+    ensureConfigDir();
+    runGoRuntime(args);
+    return;
+  }
+
+  if (args[0] === "until" || args[0] === "at") {
+    ensureConfigDir();
+    runGoRuntime(["timer", ...args]);
+    return;
+  }
+
+  const session = extractSessionFlags(args);
+  const parsed = session.ok ? parseDurationArgs(session.rest) : session;
   if (!parsed.ok) {
     process.stderr.write(`${parsed.error}\n\n`);
     printUsage();
@@ -1543,7 +2200,7 @@ function runTimer(args) {
   }
 
   const config = readConfig();
-  runClock({ mode: "timer", initialSeconds: parsed.totalSeconds, config });
+  runClock({ mode: "timer", initialSeconds: parsed.totalSeconds, config, label: session.label, tags: session.tags });
 }
 
 module.exports = {